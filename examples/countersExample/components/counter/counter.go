@@ -4,8 +4,8 @@ package counter
 
 import (
 	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
 	"github.com/google/uuid"
-	"syscall/js"
 )
 
 type Props struct{}
@@ -21,8 +21,7 @@ type Counter struct {
 	lowerID uuid.UUID
 	raiseID uuid.UUID
 
-	state    *goFE.State[counterState]
-	setState func(*counterState)
+	state *goFE.State[counterState]
 }
 
 func NewCounter(props *Props) *Counter {
@@ -31,7 +30,7 @@ func NewCounter(props *Props) *Counter {
 		lowerID: uuid.New(),
 		raiseID: uuid.New(),
 	}
-	newCounter.state, newCounter.setState = goFE.NewState[counterState](newCounter, &counterState{count: 0})
+	newCounter.state, _ = goFE.NewState[counterState](newCounter, &counterState{count: 0})
 	return newCounter
 }
 
@@ -44,16 +43,18 @@ func (c *Counter) GetChildren() []goFE.Component {
 }
 
 func (c *Counter) InitEventListeners() {
-	goFE.GetDocument().AddEventListener(c.lowerID, "click", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+	goFE.GetDocument().On(c.lowerID, "click", func(dom.Event) {
 		println("Clicked button")
-		c.setState(&counterState{count: c.state.Value.count - 1})
-		return nil
-	}))
-	goFE.GetDocument().AddEventListener(c.raiseID, "click", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		c.state.Update(func(prev *counterState) *counterState {
+			return &counterState{count: prev.count - 1}
+		})
+	})
+	goFE.GetDocument().On(c.raiseID, "click", func(dom.Event) {
 		println("Clicked button")
-		c.setState(&counterState{count: c.state.Value.count + 1})
-		return nil
-	}))
+		c.state.Update(func(prev *counterState) *counterState {
+			return &counterState{count: prev.count + 1}
+		})
+	})
 }
 
 func (c *Counter) Render() string {
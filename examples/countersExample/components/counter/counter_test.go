@@ -0,0 +1,35 @@
+package counter
+
+import (
+	"testing"
+
+	gofetest "github.com/cstevenson98/goFE/pkg/goFE/testing"
+)
+
+func TestCounter_Buttons(t *testing.T) {
+	c := NewCounter(&Props{})
+	h := gofetest.Mount(t, c)
+
+	h.Click(c.raiseID.String())
+	h.Click(c.raiseID.String())
+	h.Click(c.lowerID.String())
+
+	if got := h.Query("span").TextContent(); got != "1" {
+		t.Fatalf("count = %q, want 1", got)
+	}
+}
+
+func TestCounter_RapidClicks(t *testing.T) {
+	c := NewCounter(&Props{})
+	h := gofetest.Mount(t, c)
+
+	raise := h.Element(c.raiseID.String())
+	for i := 0; i < 3; i++ {
+		h.DOM().Dispatch(raise, "click")
+	}
+	h.Flush()
+
+	if got := h.Query("span").TextContent(); got != "3" {
+		t.Fatalf("count = %q, want 3", got)
+	}
+}
@@ -5,9 +5,9 @@ package counterStack
 import (
 	"github.com/cstevenson98/goFE/examples/countersExample/components/counter"
 	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
 	"github.com/google/uuid"
 	"math/rand"
-	"syscall/js"
 )
 
 type Props struct {
@@ -68,8 +68,7 @@ func (a *CounterStack) GetChildren() []goFE.Component {
 }
 
 func (a *CounterStack) InitEventListeners() {
-	goFE.GetDocument().AddEventListener(a.buttonID, "click", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+	goFE.GetDocument().On(a.buttonID, "click", func(dom.Event) {
 		a.setState(&counterStackState{numberOfCounters: rand.Intn(randCounterMax)})
-		return nil
-	}))
+	})
 }
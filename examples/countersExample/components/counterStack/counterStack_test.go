@@ -0,0 +1,28 @@
+package counterStack
+
+import (
+	"testing"
+
+	gofetest "github.com/cstevenson98/goFE/pkg/goFE/testing"
+)
+
+func TestCounterStack_Randomise(t *testing.T) {
+	stack := NewCounterStack(Props{Title: "Counters"})
+	h := gofetest.Mount(t, stack)
+
+	if got, want := len(h.QueryAll("span")), stack.state.Value.numberOfCounters; got != want {
+		t.Fatalf("rendered %d counters, want %d", got, want)
+	}
+	if len(stack.counters) > 0 {
+		// Randomise comes first, then minus and plus for each counter
+		h.ClickElement(h.QueryAll("button")[2])
+		if got := h.QueryAll("span")[0].TextContent(); got != "1" {
+			t.Fatalf("first counter = %q after counting up, want 1", got)
+		}
+	}
+
+	h.Click(stack.buttonID.String())
+	if got, want := len(h.QueryAll("span")), stack.state.Value.numberOfCounters; got != want {
+		t.Fatalf("rendered %d counters after randomising, want %d", got, want)
+	}
+}
@@ -5,10 +5,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
-	"syscall/js"
 	"time"
 
 	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
 	"github.com/google/uuid"
 	fetch "marwan.io/wasm-fetch"
 )
@@ -20,16 +20,16 @@ type Message struct {
 	Content string `json:"content"`
 }
 
-type messageBoardState struct {
-	Messages []Message
-}
-
 type MessageBoard struct {
 	id       uuid.UUID
 	formID   uuid.UUID
 	inputID  uuid.UUID
-	state    *goFE.State[messageBoardState]
-	setState func(*messageBoardState)
+	messages *goFE.Resource[[]Message]
+
+	// ctx is cancelled when the board is unmounted, aborting any posts
+	// still in flight.
+	ctx    context.Context
+	cancel context.CancelFunc
 }
 
 func NewMessageBoard(_ Props) *MessageBoard {
@@ -38,37 +38,30 @@ func NewMessageBoard(_ Props) *MessageBoard {
 		formID:  uuid.New(),
 		inputID: uuid.New(),
 	}
-	mb.state, mb.setState = goFE.NewState[messageBoardState](mb, &messageBoardState{})
-
-	// Initial fetch of messages
-	mb.fetchMessages()
-
+	mb.messages = goFE.NewResource(mb, fetchMessages, &goFE.ResourceOptions{Timeout: 10 * time.Second})
+	mb.ctx, mb.cancel = context.WithCancel(context.Background())
 	return mb
 }
 
-func (mb *MessageBoard) fetchMessages() {
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		res, err := fetch.Fetch("/api/messages", &fetch.Opts{
-			Method: fetch.MethodGet,
-			Signal: ctx,
-		})
-		if err != nil {
-			println("Error fetching messages:", err.Error())
-			return
-		}
-
-		var messages []Message
-		err = json.Unmarshal(res.Body, &messages)
-		if err != nil {
-			println("Error parsing messages:", err.Error())
-			return
-		}
+// OnUnmount cancels posts that have not finished yet. The messages resource
+// cancels its own requests.
+func (mb *MessageBoard) OnUnmount() {
+	mb.cancel()
+}
 
-		mb.setState(&messageBoardState{Messages: messages})
-	}()
+func fetchMessages(ctx context.Context) (*[]Message, error) {
+	res, err := fetch.Fetch("/api/messages", &fetch.Opts{
+		Method: fetch.MethodGet,
+		Signal: ctx,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var messages []Message
+	if err := json.Unmarshal(res.Body, &messages); err != nil {
+		return nil, err
+	}
+	return &messages, nil
 }
 
 func (mb *MessageBoard) GetID() uuid.UUID {
@@ -81,21 +74,23 @@ func (mb *MessageBoard) GetChildren() []goFE.Component {
 
 func (mb *MessageBoard) InitEventListeners() {
 	// Handle form submission
-	goFE.GetDocument().AddEventListener(mb.formID, "submit", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		event := args[0]
-		event.Call("preventDefault")
+	goFE.GetDocument().On(mb.formID, "submit", func(event dom.Event) {
+		event.PreventDefault()
 
 		// Get input value
-		input := js.Global().Get("document").Call("getElementById", mb.inputID.String())
-		content := input.Get("value").String()
+		input := goFE.GetDocument().DOM().GetElementByID(mb.inputID.String())
+		if input == nil {
+			return
+		}
+		content := input.Value()
 
 		if content == "" {
-			return nil
+			return
 		}
 
 		// Post new message
 		go func() {
-			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			ctx, cancel := context.WithTimeout(mb.ctx, 10*time.Second)
 			defer cancel()
 
 			body, err := json.Marshal(map[string]string{"content": content})
@@ -116,17 +111,24 @@ func (mb *MessageBoard) InitEventListeners() {
 			}
 
 			// Clear input
-			input.Set("value", "")
+			input.SetValue("")
 
 			// Refresh messages
-			mb.fetchMessages()
+			mb.messages.Refetch()
 		}()
-
-		return nil
-	}))
+	})
 }
 
 func (mb *MessageBoard) Render() string {
-	messages := mb.state.Value.Messages
-	return MessageBoardTemplate(mb.id.String(), mb.formID.String(), mb.inputID.String(), messages)
+	var messages []Message
+	if data := mb.messages.Data(); data != nil {
+		messages = *data
+	}
+	status := ""
+	if err := mb.messages.Err(); err != nil {
+		status = "Could not load messages: " + err.Error()
+	} else if mb.messages.Loading() && messages == nil {
+		status = "Loading messages..."
+	}
+	return MessageBoardTemplate(mb.id.String(), mb.formID.String(), mb.inputID.String(), status, messages)
 }
@@ -31,7 +31,8 @@ type Pokemon struct {
 }
 
 type Entry struct {
-	id uuid.UUID
+	id    uuid.UUID
+	props Props
 
 	pokemon    *goFE.State[Pokemon]
 	setPokemon func(*Pokemon)
@@ -41,6 +42,9 @@ func NewEntry(props *Props) *Entry {
 	entry := &Entry{
 		id: uuid.New(),
 	}
+	if props != nil {
+		entry.props = *props
+	}
 	entry.pokemon, entry.setPokemon = goFE.NewState[Pokemon](entry, nil)
 	go func() {
 		if props != nil {
@@ -68,6 +72,20 @@ func (e *Entry) GetID() uuid.UUID {
 	return e.id
 }
 
+// Key identifies the entry by the pokemon it shows, so reordered search
+// results keep their already-fetched entries.
+func (e *Entry) Key() string {
+	return strconv.Itoa(e.props.PokemonID)
+}
+
+// SetProps receives the entry's props when it survives a list update. The
+// key is the pokemon ID, so the fetched pokemon is still current.
+func (e *Entry) SetProps(props *Props) {
+	if props != nil {
+		e.props = *props
+	}
+}
+
 func (e *Entry) Render() string {
 	return EntryTemplate(e.id.String(), e.pokemon.Value)
 }
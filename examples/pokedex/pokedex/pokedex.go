@@ -4,12 +4,13 @@ package pokedex
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"strings"
-	"syscall/js"
 	"time"
 
 	"github.com/cstevenson98/goFE/examples/pokedex/components/entry"
 	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
 	"github.com/google/uuid"
 	fetch "marwan.io/wasm-fetch"
 )
@@ -31,15 +32,14 @@ func FilterResultByName(name *string, list PokemonResultsList) []int {
 }
 
 type Pokedex struct {
-	id               uuid.UUID
-	formID           uuid.UUID
-	inputID          uuid.UUID
-	state            *goFE.State[pokedexState]
-	setState         func(*pokedexState)
-	searchTerm       *goFE.State[string]
-	setSearchTerm    func(*string)
-	searchResults    *goFE.State[[]int]
-	setSearchResults func(*[]int)
+	id            uuid.UUID
+	formID        uuid.UUID
+	inputID       uuid.UUID
+	state         *goFE.State[pokedexState]
+	setState      func(*pokedexState)
+	searchTerm    *goFE.State[string]
+	setSearchTerm func(*string)
+	searchResults *goFE.Computed[[]int]
 
 	inputValue string
 
@@ -65,15 +65,10 @@ func NewPokedex(_ Props) *Pokedex {
 	}
 
 	pokedex.state, pokedex.setState = goFE.NewState[pokedexState](pokedex, &pokedexState{})
-	pokedex.searchResults, pokedex.setSearchResults = goFE.NewState[[]int](pokedex, &[]int{})
 	pokedex.searchTerm, pokedex.setSearchTerm = goFE.NewState[string](pokedex, nil)
-	pokedex.searchTerm.AddEffect(func(value *string) {
-		indices := FilterResultByName(value, pokedex.state.Value.allPokemon)
-		pokedex.setSearchResults(&indices)
-	})
-	pokedex.state.AddEffect(func(value *pokedexState) {
-		indices := FilterResultByName(pokedex.searchTerm.Value, value.allPokemon)
-		pokedex.setSearchResults(&indices)
+	pokedex.searchResults = goFE.NewComputed(pokedex, func() *[]int {
+		indices := FilterResultByName(pokedex.searchTerm.Get(), pokedex.state.Get().allPokemon)
+		return &indices
 	})
 
 	go func() { // Async fetch of all pokemon
@@ -103,18 +98,16 @@ func (p *Pokedex) GetID() uuid.UUID {
 
 func (p *Pokedex) Render() string {
 	var newProps []*entry.Props
-	if p.searchResults.Value != nil {
-		for i, index := range *p.searchResults.Value {
-			newProps = append(newProps, &entry.Props{
-				PokemonID: index,
-			})
-			if i >= initialLimit {
-				break
-			}
+	for i, index := range *p.searchResults.Get() {
+		newProps = append(newProps, &entry.Props{
+			PokemonID: index,
+		})
+		if i >= initialLimit {
+			break
 		}
 	}
-	goFE.UpdateComponentArray[*entry.Entry, entry.Props](&p.entries, len(newProps), entry.NewEntry, newProps)
-	value := p.searchTerm.Value
+	goFE.UpdateKeyedComponentArray[*entry.Entry, entry.Props](&p.entries, newProps, entryKey, entry.NewEntry)
+	value := p.searchTerm.Get()
 	if value == nil {
 		newValue := ""
 		value = &newValue
@@ -122,6 +115,10 @@ func (p *Pokedex) Render() string {
 	return PokedexTemplate(p.id.String(), p.formID.String(), p.inputID.String(), *value, goFE.RenderChildren(p))
 }
 
+func entryKey(props *entry.Props) string {
+	return strconv.Itoa(props.PokemonID)
+}
+
 func (p *Pokedex) GetChildren() []goFE.Component {
 	var out []goFE.Component
 	for _, child := range p.entries {
@@ -131,14 +128,11 @@ func (p *Pokedex) GetChildren() []goFE.Component {
 }
 
 func (p *Pokedex) InitEventListeners() {
-	goFE.GetDocument().AddEventListener(p.formID, "submit", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		event := args[0]
-		event.Call("preventDefault")
+	goFE.GetDocument().On(p.formID, "submit", func(event dom.Event) {
+		event.PreventDefault()
 		p.setSearchTerm(&p.inputValue)
-		return nil
-	}))
-	goFE.GetDocument().AddEventListener(p.inputID, "input", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		p.inputValue = this.Get("value").String()
-		return nil
-	}))
+	})
+	goFE.GetDocument().On(p.inputID, "input", func(event dom.Event) {
+		p.inputValue = event.Value()
+	})
 }
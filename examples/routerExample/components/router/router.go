@@ -4,7 +4,6 @@ package router
 
 import (
 	"strings"
-	"syscall/js"
 
 	"github.com/cstevenson98/goFE/examples/messageBoard/messageBoard"
 	"github.com/cstevenson98/goFE/examples/pokedex/pokedex"
@@ -12,6 +11,7 @@ import (
 	"github.com/cstevenson98/goFE/examples/routerExample/components/contact"
 	"github.com/cstevenson98/goFE/examples/routerExample/components/home"
 	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
 	"github.com/google/uuid"
 )
 
@@ -35,7 +35,7 @@ type Router struct {
 	setState         func(*routerState)
 	routes           map[string]ViewCreator
 	currentView      *goFE.SwappableComponent
-	popStateFunc     js.Func
+	removePopState   func()
 	lastRenderedPath string // Track the last path that was rendered
 }
 
@@ -56,7 +56,7 @@ func NewRouter(_ Props) *Router {
 	}
 
 	// Get initial path from browser
-	initialPath := js.Global().Get("window").Get("location").Get("pathname").String()
+	initialPath := goFE.GetDocument().DOM().Location().Pathname
 	if initialPath == "" {
 		initialPath = "/"
 	}
@@ -131,45 +131,53 @@ func (r *Router) InitEventListeners() {
 
 	// Setup click listeners for navigation links
 	doc := goFE.GetDocument()
-	doc.AddEventListener(r.navContainer, "click", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		// Get the event object
-		event := args[0]
-
+	doc.On(r.navContainer, "click", func(event dom.Event) {
 		// Get the target element that was clicked
-		target := event.Get("target")
+		target := event.Target()
 
 		// Check if we clicked on an anchor tag
-		if target.Get("tagName").String() == "A" {
+		if target != nil && target.NodeName() == "A" {
 			// Prevent default navigation
-			event.Call("preventDefault")
+			event.PreventDefault()
 
 			// Get the href attribute safely
-			href := target.Call("getAttribute", "href").String()
+			href, _ := target.GetAttribute("href")
 
 			println("Router: Navigation link clicked, href:", href)
 
 			// Navigate to the new path
 			r.navigateTo(href)
 		}
-		return nil
-	}))
+	})
+}
 
-	// Handle browser back/forward buttons with popstate event
-	r.popStateFunc = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
-		path := js.Global().Get("window").Get("location").Get("pathname").String()
+// OnMount handles the browser back/forward buttons with a popstate listener
+// on the window, which lives as long as the router is mounted
+func (r *Router) OnMount() {
+	doc := goFE.GetDocument()
+	println("Router: Adding popstate event listener")
+	r.removePopState = doc.DOM().AddWindowListener("popstate", func(dom.Event) {
+		path := doc.DOM().Location().Pathname
 		if path == "" {
 			path = "/"
 		}
 
 		println("Router: Popstate event detected, path:", path)
 
-		// Fully update the view when the popstate event occurs
-		r.navigateTo(path)
-		return nil
+		// The browser has already moved to path, so only update the view;
+		// pushing it again would cut off the forward history
+		if r.state.Value.currentPath != path {
+			r.setState(&routerState{
+				currentPath: path,
+			})
+		}
 	})
+}
 
-	println("Router: Adding popstate event listener")
-	js.Global().Get("window").Call("addEventListener", "popstate", r.popStateFunc)
+// OnUnmount removes the popstate listener
+func (r *Router) OnUnmount() {
+	println("Router: Removing popstate event listener")
+	r.removePopState()
 }
 
 // navigateTo navigates to a specific path
@@ -183,7 +191,7 @@ func (r *Router) navigateTo(path string) {
 	}
 
 	// Update the URL in the browser
-	js.Global().Get("window").Get("history").Call("pushState", nil, "", path)
+	goFE.GetDocument().DOM().PushState(path)
 
 	// Update the component state
 	r.setState(&routerState{
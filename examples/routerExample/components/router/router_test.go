@@ -0,0 +1,37 @@
+package router
+
+import (
+	"testing"
+
+	"github.com/cstevenson98/goFE/examples/routerExample/components/about"
+	"github.com/cstevenson98/goFE/examples/routerExample/components/contact"
+	"github.com/cstevenson98/goFE/examples/routerExample/components/home"
+	gofetest "github.com/cstevenson98/goFE/pkg/goFE/testing"
+)
+
+func TestRouter_Navigation(t *testing.T) {
+	h := gofetest.New(t)
+	h.DOM().PushState("/contact")
+	r := NewRouter(Props{})
+	h.Mount(r)
+	if _, ok := r.currentView.GetCurrent().(*contact.Contact); !ok {
+		t.Fatalf("initial view is %T, want the contact page", r.currentView.GetCurrent())
+	}
+
+	h.ClickElement(h.Query(`nav a[href="/about"]`))
+	if _, ok := r.currentView.GetCurrent().(*about.About); !ok {
+		t.Fatalf("view after clicking About is %T", r.currentView.GetCurrent())
+	}
+	if got := h.DOM().Location().Pathname; got != "/about" {
+		t.Fatalf("location after clicking About = %q", got)
+	}
+	if h.Element(r.currentView.GetCurrent().GetID().String()) == nil {
+		t.Fatal("about page was not rendered")
+	}
+
+	h.Back()
+	h.Back()
+	if _, ok := r.currentView.GetCurrent().(*home.Home); !ok {
+		t.Fatalf("view after going back twice is %T, want the home page", r.currentView.GetCurrent())
+	}
+}
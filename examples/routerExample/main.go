@@ -14,15 +14,13 @@ func main() {
 		Level: goFE.DEBUG,
 	})
 
-	// Create and set up the router with route definitions
-	println("RouterExample: Creating router component")
-	routerComponent := router.NewRouter(router.Props{})
+	// Set up the document first, the router reads the initial path from it
+	println("RouterExample: Setting up document")
+	goFE.SetDocument(goFE.NewDocument(nil))
 
-	// Set up the document with the router as the root component
-	println("RouterExample: Setting up document with router as root component")
-	goFE.SetDocument(goFE.NewDocument([]goFE.Component{
-		routerComponent,
-	}))
+	// Create the router with route definitions as the root component
+	println("RouterExample: Creating router component")
+	goFE.GetDocument().Append(router.NewRouter(router.Props{}))
 
 	// Initialize the document
 	println("RouterExample: Initializing document")
@@ -0,0 +1,85 @@
+package goFE
+
+import (
+	"sync"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+)
+
+var appsLock sync.Mutex
+
+// apps holds the apps created with Mount that are still mounted.
+var apps []*App
+
+// App is a component tree mounted into an element of an existing page with
+// Mount, independently of the default document and of other apps. Several
+// widgets can be embedded in a server-rendered page this way, each with its
+// own element and event handlers.
+//
+// Apps share one scheduler, started by Init or the first Mount: state
+// updates from every app are applied together and each app patches only its
+// own element, once per frame.
+type App struct {
+	document *Document
+}
+
+// Mount renders components into the element with the id elementID of the
+// page and returns the app, or nil if there is no such element. It starts the
+// scheduler if Init has not, so apps work without a default document.
+//
+// Apps are isolated in what they render and handle, not in how updates run:
+// every app shares the one scheduler, so a slow effect in one app holds up
+// the others, and everything but the app's document (states, effects and the
+// scheduler) logs through the logger passed to Init rather than App.Logger.
+func Mount(elementID string, components ...Component) *App {
+	startScheduler()
+	return MountWithDOM(defaultDOM(), elementID, components...)
+}
+
+// MountWithDOM is Mount for the given DOM. It does not start the scheduler:
+// with an in-memory DOM, as in tests, updates are applied by Flush.
+func MountWithDOM(d dom.DOM, elementID string, components ...Component) *App {
+	doc := NewDocumentWithDOM(components, d)
+	doc.rootID = elementID
+	doc.logger = &Logger{Level: logger.Level, Name: elementID, Sinks: logger.Sinks}
+	if d.GetElementByID(elementID) == nil {
+		doc.log(ERROR, "No element to mount the app into", String("rootID", elementID))
+		return nil
+	}
+	app := &App{document: doc}
+	appsLock.Lock()
+	apps = append(apps, app)
+	appsLock.Unlock()
+	doc.Init()
+	return app
+}
+
+// Document returns the document the app is mounted in.
+func (a *App) Document() *Document {
+	return a.document
+}
+
+// Logger returns the app's logger, named after its element, which starts
+// with the level and sinks of the one passed to Init. It gets the messages
+// of the app's document (mounting, rendering and event handling), so
+// changing it only affects those; states, the scheduler and the rest of the
+// package keep logging through the logger passed to Init.
+func (a *App) Logger() *Logger {
+	return a.document.logger
+}
+
+// Unmount tears the app down: its components are unmounted and their states
+// killed, its event listeners removed and its element emptied. The rest of
+// the page is left alone.
+func (a *App) Unmount() {
+	a.document.Unmount()
+
+	appsLock.Lock()
+	defer appsLock.Unlock()
+	for i, app := range apps {
+		if app == a {
+			apps = append(apps[:i], apps[i+1:]...)
+			break
+		}
+	}
+}
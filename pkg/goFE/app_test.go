@@ -0,0 +1,121 @@
+package goFE
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+// widget registers its handler through GetDocument, like components outside
+// this package do.
+type widget struct {
+	id       uuid.UUID
+	state    *State[int]
+	setState func(*int)
+}
+
+func newWidget() *widget {
+	w := &widget{id: uuid.New()}
+	w.state, w.setState = NewState[int](w, new(int))
+	return w
+}
+
+func (w *widget) Render() string {
+	return `<button id="` + w.id.String() + `">` + strconv.Itoa(*w.state.Value) + `</button>`
+}
+func (w *widget) GetID() uuid.UUID         { return w.id }
+func (w *widget) GetChildren() []Component { return nil }
+func (w *widget) InitEventListeners() {
+	GetDocument().On(w.id, "click", func(dom.Event) {
+		w.state.Update(func(prev *int) *int {
+			next := *prev + 1
+			return &next
+		})
+	})
+}
+
+func TestMount_IndependentApps(t *testing.T) {
+	memory := dom.NewMemory()
+	for _, id := range []string{"cart", "search"} {
+		element := memory.CreateElement("div")
+		element.SetAttribute("id", id)
+		memory.Body().AppendChild(element)
+	}
+	cart, search := newWidget(), newWidget()
+	cartApp := MountWithDOM(memory, "cart", cart)
+	searchApp := MountWithDOM(memory, "search", search)
+	defer searchApp.Unmount()
+	if GetDocument() != nil {
+		t.Fatal("Mount replaced the default document")
+	}
+
+	memory.Dispatch(memory.GetElementByID(cart.id.String()), "click")
+	Flush()
+	if got := memory.GetElementByID(cart.id.String()).TextContent(); got != "1" {
+		t.Fatalf("cart rendered %q after a click", got)
+	}
+	if got := memory.GetElementByID(search.id.String()).TextContent(); got != "0" {
+		t.Fatalf("search rendered %q after a click in the cart", got)
+	}
+
+	button := memory.GetElementByID(cart.id.String())
+	cartApp.Unmount()
+	if memory.GetElementByID("cart").ChildCount() != 0 {
+		t.Fatal("Unmount left the app's markup behind")
+	}
+	memory.Dispatch(button, "click")
+	memory.Dispatch(memory.GetElementByID(search.id.String()), "click")
+	Flush()
+	if *cart.state.Value != 1 {
+		t.Fatal("an unmounted app still handled events")
+	}
+	if got := memory.GetElementByID(search.id.String()).TextContent(); got != "1" {
+		t.Fatalf("search rendered %q after the cart was unmounted", got)
+	}
+}
+
+func TestMount_DocumentOfEffectsAndGoroutines(t *testing.T) {
+	memory := dom.NewMemory()
+	for _, id := range []string{"cart", "search"} {
+		element := memory.CreateElement("div")
+		element.SetAttribute("id", id)
+		memory.Body().AppendChild(element)
+	}
+	cart, search := newWidget(), newWidget()
+	cartApp := MountWithDOM(memory, "cart", cart)
+	defer cartApp.Unmount()
+	searchApp := MountWithDOM(memory, "search", search)
+	defer searchApp.Unmount()
+
+	var seen *Document
+	search.state.AddEffect(func(*int) { seen = GetDocument() })
+	memory.Dispatch(memory.GetElementByID(search.id.String()), "click")
+	Flush()
+	if seen != searchApp.Document() {
+		t.Fatal("an effect did not see the document of its app")
+	}
+
+	// While the cart runs code on this goroutine, others are unaffected
+	leave := cartApp.Document().enter()
+	other := make(chan *Document)
+	go func() { other <- GetDocument() }()
+	if got := <-other; got != nil {
+		t.Fatal("another goroutine got the document entered on this one")
+	}
+	if GetDocument() != cartApp.Document() {
+		t.Fatal("the entered document was not returned")
+	}
+	leave()
+	if GetDocument() != nil {
+		t.Fatal("the document was still returned after leaving it")
+	}
+}
+
+func TestMount_MissingElement(t *testing.T) {
+	if app := MountWithDOM(dom.NewMemory(), "missing", newWidget()); app != nil {
+		app.Unmount()
+		t.Fatal("mounted into a missing element")
+	}
+}
@@ -0,0 +1,166 @@
+package goFE
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"runtime/debug"
+	"sync"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+// ErrorBoundary contains panics raised by the components below it: while
+// rendering them, in their event handlers, in the effects of their states
+// and in their lifecycle hooks. Instead of taking the whole program down,
+// the panic is logged and the boundary renders a fallback view in place of
+// its children until it is reset.
+//
+// Panics outside any boundary are not recovered.
+type ErrorBoundary struct {
+	id       uuid.UUID
+	resetID  uuid.UUID
+	children []Component
+	fallback func(err error, resetID string) string
+
+	lock sync.Mutex
+	err  error
+}
+
+// NewErrorBoundary wraps children in a boundary. When one of them panics,
+// fallback renders the view shown instead, given the error and the id of an
+// element that resets the boundary when clicked; nil gives a plain message
+// with a "Try again" button.
+func NewErrorBoundary(fallback func(err error, resetID string) string, children ...Component) *ErrorBoundary {
+	if fallback == nil {
+		fallback = defaultFallback
+	}
+	boundary := &ErrorBoundary{
+		id:       uuid.New(),
+		resetID:  uuid.New(),
+		children: children,
+		fallback: fallback,
+	}
+	// Children hidden by a failure are not returned by GetChildren, so they
+	// are torn down here instead
+	onTeardown(boundary, func() {
+		if boundary.Err() != nil {
+			for _, child := range boundary.children {
+				killAllStates(child)
+			}
+		}
+	})
+	return boundary
+}
+
+func defaultFallback(err error, resetID string) string {
+	return `<div role="alert"><p>Something went wrong: ` + html.EscapeString(err.Error()) +
+		`</p><button id="` + resetID + `" type="button">Try again</button></div>`
+}
+
+func (b *ErrorBoundary) Render() (out string) {
+	open := `<div id="` + b.id.String() + `">`
+	if err := b.Err(); err != nil {
+		return open + b.fallback(err, b.resetID.String()) + `</div>`
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			b.fail(b.id, r)
+			out = open + b.fallback(b.Err(), b.resetID.String()) + `</div>`
+		}
+	}()
+	return open + RenderChildren(b) + `</div>`
+}
+
+func (b *ErrorBoundary) GetID() uuid.UUID {
+	return b.id
+}
+
+// GetChildren returns the children, or none while the fallback is shown.
+// Hidden children keep their state, so resetting renders them again.
+func (b *ErrorBoundary) GetChildren() []Component {
+	if b.Err() != nil {
+		return nil
+	}
+	return b.children
+}
+
+func (b *ErrorBoundary) InitEventListeners() {
+	GetDocument().On(b.resetID, "click", func(dom.Event) {
+		b.Reset()
+	})
+}
+
+// Err returns the error the boundary caught, or nil if it is showing its
+// children.
+func (b *ErrorBoundary) Err() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.err
+}
+
+// Reset clears the caught error and renders the children again.
+func (b *ErrorBoundary) Reset() {
+	b.lock.Lock()
+	b.err = nil
+	b.lock.Unlock()
+	sched.markDirty(b)
+	sched.notify()
+}
+
+// fail records a panic raised by the component with the given id and
+// schedules the fallback to be rendered.
+func (b *ErrorBoundary) fail(id uuid.UUID, recovered interface{}) {
+	err, ok := recovered.(error)
+	if !ok {
+		err = errors.New(fmt.Sprint(recovered))
+	}
+	logger.log("boundary", ERROR, "Recovered from panic", ComponentID(id), Err(err), String("stack", string(debug.Stack())))
+	b.lock.Lock()
+	if b.err == nil {
+		b.err = err
+	}
+	b.lock.Unlock()
+	sched.markDirty(b)
+	sched.notify()
+}
+
+// guard runs fn, which belongs to the component with the given id. If fn
+// panics, the closest ErrorBoundary above that component records the panic,
+// the fallback is scheduled to render and guard reports true; without a
+// boundary the panic carries on.
+func guard(id uuid.UUID, fn func()) (recovered bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			boundary := boundaryOf(rootsOf(id), id, nil)
+			if boundary == nil {
+				panic(r)
+			}
+			boundary.fail(id, r)
+			recovered = true
+		}
+	}()
+	fn()
+	return false
+}
+
+// boundaryOf returns the closest ErrorBoundary strictly above the component
+// with the given id, looking through the children boundaries are hiding
+// too. inner is the closest boundary above components.
+func boundaryOf(components []Component, id uuid.UUID, inner *ErrorBoundary) *ErrorBoundary {
+	for _, component := range components {
+		if component.GetID() == id {
+			return inner
+		}
+		children := component.GetChildren()
+		next := inner
+		if boundary, ok := component.(*ErrorBoundary); ok {
+			children, next = boundary.children, boundary
+		}
+		if found := boundaryOf(children, id, next); found != nil {
+			return found
+		}
+	}
+	return nil
+}
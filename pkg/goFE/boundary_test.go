@@ -0,0 +1,113 @@
+package goFE
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+type bomb struct {
+	id, renderID, handlerID uuid.UUID
+	armed                   *bool
+	state                   *State[int]
+	setState                func(*int)
+}
+
+func newBomb(armed *bool) *bomb {
+	b := &bomb{id: uuid.New(), renderID: uuid.New(), handlerID: uuid.New(), armed: armed}
+	b.state, b.setState = NewState[int](b, new(int))
+	return b
+}
+
+func (b *bomb) Render() string {
+	if *b.armed && *b.state.Value > 0 {
+		panic("render exploded")
+	}
+	return `<div id="` + b.id.String() + `"><button id="` + b.renderID.String() + `"></button><button id="` +
+		b.handlerID.String() + `"></button></div>`
+}
+func (b *bomb) GetID() uuid.UUID         { return b.id }
+func (b *bomb) GetChildren() []Component { return nil }
+func (b *bomb) InitEventListeners() {
+	document.On(b.renderID, "click", func(dom.Event) {
+		one := 1
+		b.setState(&one)
+	})
+	document.On(b.handlerID, "click", func(dom.Event) {
+		panic("handler exploded")
+	})
+}
+
+func TestErrorBoundary_ContainsPanics(t *testing.T) {
+	armed := true
+	child := newBomb(&armed)
+	boundary := NewErrorBoundary(nil, child)
+	memory := dom.NewMemory()
+	SetDocument(NewDocumentWithDOM([]Component{boundary}, memory))
+	defer SetDocument(nil)
+	document.Init()
+	defer killAllStates(boundary)
+	click := func(id uuid.UUID) {
+		memory.Dispatch(memory.GetElementByID(id.String()), "click")
+		Flush()
+	}
+	text := func() string { return memory.GetElementByID(boundary.id.String()).TextContent() }
+
+	click(child.renderID)
+	if !strings.Contains(text(), "render exploded") || memory.GetElementByID(child.id.String()) != nil {
+		t.Fatalf("fallback not shown after a render panic: %q", text())
+	}
+
+	armed = false
+	click(boundary.resetID)
+	if boundary.Err() != nil || memory.GetElementByID(child.id.String()) == nil {
+		t.Fatal("reset did not render the children again")
+	}
+
+	click(child.handlerID)
+	if !strings.Contains(text(), "handler exploded") {
+		t.Fatalf("fallback not shown after a handler panic: %q", text())
+	}
+}
+
+func TestErrorBoundary_InitialRenderAndEffects(t *testing.T) {
+	armed := true
+	child := newBomb(&armed)
+	one := 1
+	child.state.Value = &one
+	boundary := NewErrorBoundary(func(err error, resetID string) string {
+		return `<p>` + err.Error() + `</p>`
+	}, child)
+	other := newBomb(new(bool))
+	other.state.AddEffect(func(*int) { panic("effect exploded") })
+	effects := NewErrorBoundary(nil, other)
+
+	memory := dom.NewMemory()
+	SetDocument(NewDocumentWithDOM([]Component{boundary, effects}, memory))
+	defer SetDocument(nil)
+	document.Init()
+	defer killAllStates(boundary)
+	defer killAllStates(effects)
+
+	if got := memory.GetElementByID(boundary.id.String()).TextContent(); got != "render exploded" {
+		t.Fatalf("initial render = %q", got)
+	}
+	other.setState(&one)
+	Flush()
+	if got := effects.Err(); got == nil || got.Error() != "effect exploded" {
+		t.Fatalf("effect panic caught as %v", got)
+	}
+}
+
+func TestGuard_WithoutBoundaryPanics(t *testing.T) {
+	SetDocument(NewDocumentWithDOM(nil, dom.NewMemory()))
+	defer SetDocument(nil)
+	defer func() {
+		if recover() == nil {
+			t.Fatal("panic outside a boundary was swallowed")
+		}
+	}()
+	guard(uuid.New(), func() { panic("boom") })
+}
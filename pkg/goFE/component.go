@@ -8,3 +8,26 @@ type Component interface {
 	GetChildren() []Component
 	InitEventListeners()
 }
+
+// Mounter is implemented by components that need to set something up once
+// they are in the DOM, such as starting a fetch or adding a window listener.
+// OnMount runs once, after the component's first render has been applied and
+// its listeners initialised, and after the OnMount of its children.
+type Mounter interface {
+	OnMount()
+}
+
+// Updater is implemented by components that need to act after their DOM has
+// been patched by a re-render, e.g. to measure or scroll an element.
+type Updater interface {
+	OnUpdate()
+}
+
+// Unmounter is implemented by components that need to undo what OnMount set
+// up. OnUnmount runs once when a mounted component is torn down: when it is
+// swapped out of a SwappableComponent, dropped by UpdateComponentArray or
+// UpdateKeyedComponentArray, or when an ancestor is. A component runs
+// OnUnmount before its children.
+type Unmounter interface {
+	OnUnmount()
+}
@@ -0,0 +1,78 @@
+package form
+
+import (
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/html"
+)
+
+// CheckboxProps configures a Checkbox.
+type CheckboxProps struct {
+	Name    string
+	Label   string
+	Checked bool
+	// Required makes the checkbox have to be checked to submit.
+	Required bool
+	Disabled bool
+	// Error is shown under the checkbox, which is marked invalid meanwhile.
+	Error     string
+	ClassName string
+	// OnChange is called when the checkbox is checked or unchecked.
+	OnChange func(checked bool)
+}
+
+// Checkbox is a labelled checkbox.
+type Checkbox struct {
+	field
+	props      CheckboxProps
+	checked    *goFE.State[bool]
+	setChecked func(*bool)
+}
+
+// NewCheckbox creates a checkbox.
+func NewCheckbox(props CheckboxProps) *Checkbox {
+	c := &Checkbox{props: props}
+	c.init(c, props.Name, props.Error)
+	checked := props.Checked
+	c.checked, c.setChecked = goFE.NewState[bool](c, &checked)
+	return c
+}
+
+func (c *Checkbox) Render() string {
+	return c.events.Render(c.id,
+		html.Div(html.ID(c.id), html.Class("gofe-field gofe-field-checkbox"),
+			html.Input(html.ID(c.controlID), html.Type("checkbox"),
+				html.If(c.props.Name != "", html.Name(c.props.Name)),
+				html.If(c.props.ClassName != "", html.Class(c.props.ClassName)),
+				html.Checked(c.Checked()),
+				html.Required(c.props.Required),
+				html.Disabled(c.props.Disabled),
+				c.invalid(),
+				html.On("change", func(event dom.Event) {
+					checked := event.Target().Checked()
+					c.setChecked(&checked)
+					if c.props.OnChange != nil {
+						c.props.OnChange(checked)
+					}
+				}),
+			),
+			c.label(c.props.Label),
+			c.errorMessage(),
+		),
+	)
+}
+
+// Checked reports whether the checkbox is checked.
+func (c *Checkbox) Checked() bool {
+	return *c.checked.Get()
+}
+
+// SetChecked checks or unchecks the checkbox, without calling OnChange.
+func (c *Checkbox) SetChecked(checked bool) {
+	c.setChecked(&checked)
+}
+
+// FieldValue implements Field.
+func (c *Checkbox) FieldValue() interface{} {
+	return c.Checked()
+}
@@ -0,0 +1,95 @@
+// Package form provides the standard form controls: Input, Textarea,
+// Select, Checkbox and Radio, and a Form collecting their values on submit.
+// Each control renders its label, and its error message linked with
+// aria-invalid and aria-describedby, so forms are accessible by default.
+// Controls are unstyled apart from the props' ClassName and a few
+// gofe-field* classes.
+package form
+
+import (
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/html"
+	"github.com/google/uuid"
+)
+
+// Field is a control whose value a Form collects on submit.
+type Field interface {
+	goFE.Component
+	// FieldName is the key of the value in the submitted data: the Name
+	// prop, or the component's id if it has none.
+	FieldName() string
+	// FieldValue is the current value: a string, or a bool for checkboxes.
+	FieldValue() interface{}
+}
+
+// field holds what every control has besides its value.
+type field struct {
+	id        uuid.UUID
+	controlID uuid.UUID
+	errorID   uuid.UUID
+	name      string
+	events    html.Events
+
+	err    *goFE.State[string]
+	setErr func(*string)
+}
+
+func (f *field) init(owner goFE.Component, name, err string) {
+	f.id, f.controlID, f.errorID = uuid.New(), uuid.New(), uuid.New()
+	f.name = name
+	f.err, f.setErr = goFE.NewState[string](owner, &err)
+}
+
+func (f *field) GetID() uuid.UUID {
+	return f.id
+}
+
+func (f *field) GetChildren() []goFE.Component {
+	return nil
+}
+
+func (f *field) InitEventListeners() {
+	f.events.Bind()
+}
+
+// FieldName implements Field.
+func (f *field) FieldName() string {
+	if f.name != "" {
+		return f.name
+	}
+	return f.id.String()
+}
+
+// Error returns the error message shown under the control.
+func (f *field) Error() string {
+	return *f.err.Get()
+}
+
+// SetError shows message under the control and marks it invalid; "" clears
+// it.
+func (f *field) SetError(message string) {
+	f.setErr(&message)
+}
+
+// invalid marks the control invalid and points it at its error message,
+// when there is one.
+func (f *field) invalid() html.Node {
+	return html.If(f.Error() != "",
+		html.Aria("invalid", "true"),
+		html.Aria("describedby", f.errorID.String()),
+	)
+}
+
+// errorMessage renders the error message, if any.
+func (f *field) errorMessage() html.Node {
+	return html.If(f.Error() != "",
+		html.P(html.ID(f.errorID), html.Class("gofe-field-error"), html.Role("alert"), html.Text(f.Error())),
+	)
+}
+
+// label renders the label of the control, if any.
+func (f *field) label(text string) html.Node {
+	return html.If(text != "",
+		html.Label(html.For(f.controlID.String()), html.Text(text)),
+	)
+}
@@ -0,0 +1,85 @@
+package form
+
+import (
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/html"
+	"github.com/google/uuid"
+)
+
+// FormProps configures a Form.
+type FormProps struct {
+	// OnSubmit is called with the value of every Field among the children,
+	// keyed by FieldName. It runs in a goroutine of its own, so it may block,
+	// e.g. on a request.
+	OnSubmit func(data map[string]interface{})
+	Children []goFE.Component
+	// SubmitLabel is the text of the submit button; "" means "Submit".
+	SubmitLabel string
+	ClassName   string
+}
+
+// Form renders its children in a form with a submit button. The browser
+// checks required fields before submitting.
+type Form struct {
+	id     uuid.UUID
+	props  FormProps
+	events html.Events
+}
+
+// NewForm creates a form.
+func NewForm(props FormProps) *Form {
+	if props.SubmitLabel == "" {
+		props.SubmitLabel = "Submit"
+	}
+	return &Form{id: uuid.New(), props: props}
+}
+
+func (f *Form) Render() string {
+	return f.events.Render(f.id,
+		html.Form(html.ID(f.id), html.Class("gofe-form"),
+			html.If(f.props.ClassName != "", html.Class(f.props.ClassName)),
+			html.OnSubmit(func(event dom.Event) {
+				event.PreventDefault()
+				// Collect once the input typed just before submitting is applied
+				goFE.Atomic(func() {
+					if f.props.OnSubmit != nil {
+						// Off the scheduler, which OnSubmit must not hold up
+						go f.props.OnSubmit(f.Data())
+					}
+				})
+			}),
+			html.Children(f),
+			html.Button(html.Type("submit"), html.Text(f.props.SubmitLabel)),
+		),
+	)
+}
+
+func (f *Form) GetID() uuid.UUID {
+	return f.id
+}
+
+func (f *Form) GetChildren() []goFE.Component {
+	return f.props.Children
+}
+
+func (f *Form) InitEventListeners() {
+	f.events.Bind()
+}
+
+// Data returns the value of every Field among the descendants, keyed by
+// FieldName.
+func (f *Form) Data() map[string]interface{} {
+	data := make(map[string]interface{})
+	var collect func(components []goFE.Component)
+	collect = func(components []goFE.Component) {
+		for _, component := range components {
+			if field, ok := component.(Field); ok {
+				data[field.FieldName()] = field.FieldValue()
+			}
+			collect(component.GetChildren())
+		}
+	}
+	collect(f.props.Children)
+	return data
+}
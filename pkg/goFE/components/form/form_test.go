@@ -0,0 +1,221 @@
+package form_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/components/form"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	gofetest "github.com/cstevenson98/goFE/pkg/goFE/testing"
+)
+
+// attr returns the value of the attribute name of node, or "".
+func attr(node dom.Node, name string) string {
+	value, _ := node.GetAttribute(name)
+	return value
+}
+
+// has reports whether node has the attribute name.
+func has(node dom.Node, name string) bool {
+	_, ok := node.GetAttribute(name)
+	return ok
+}
+
+func TestInput_LabelChangeAndError(t *testing.T) {
+	var changes []string
+	focused := false
+	input := form.NewInput(form.InputProps{
+		Type:        "email",
+		Name:        "email",
+		Label:       "Email",
+		Placeholder: "you@example.com",
+		Required:    true,
+		OnChange:    func(value string) { changes = append(changes, value) },
+		OnFocus:     func() { focused = true },
+	})
+	h := gofetest.Mount(t, input)
+
+	control := h.Query("input[name=email]")
+	if control == nil {
+		t.Fatalf("no input in:\n%s", h.HTML())
+	}
+	label := h.Query("label")
+	if label == nil || label.TextContent() != "Email" || attr(label, "for") != attr(control, "id") {
+		t.Fatalf("label not linked to the input in:\n%s", h.HTML())
+	}
+	if attr(control, "type") != "email" || attr(control, "placeholder") != "you@example.com" || !has(control, "required") {
+		t.Fatalf("props not rendered in:\n%s", h.HTML())
+	}
+
+	h.Fire(attr(control, "id"), "focus")
+	h.Input(attr(control, "id"), "ada@example.com")
+	if !focused || !reflect.DeepEqual(changes, []string{"ada@example.com"}) || input.Value() != "ada@example.com" {
+		t.Fatalf("focused %v, changes %q, value %q", focused, changes, input.Value())
+	}
+
+	input.SetError("Enter a valid email")
+	goFE.Flush()
+	control = h.Query("input[name=email]")
+	message := h.Query("p.gofe-field-error")
+	if message == nil || message.TextContent() != "Enter a valid email" || attr(message, "role") != "alert" {
+		t.Fatalf("error not shown in:\n%s", h.HTML())
+	}
+	if attr(control, "aria-invalid") != "true" || attr(control, "aria-describedby") != attr(message, "id") {
+		t.Fatalf("input not marked invalid in:\n%s", h.HTML())
+	}
+
+	input.SetError("")
+	goFE.Flush()
+	if h.Query("p.gofe-field-error") != nil || h.Query("input[aria-invalid]") != nil {
+		t.Fatalf("error not cleared in:\n%s", h.HTML())
+	}
+}
+
+func TestControls_Disabled(t *testing.T) {
+	h := gofetest.Mount(t,
+		form.NewInput(form.InputProps{Name: "a", Disabled: true}),
+		form.NewTextarea(form.TextareaProps{Name: "b", Disabled: true}),
+		form.NewSelect(form.SelectProps{Name: "c", Disabled: true}),
+		form.NewCheckbox(form.CheckboxProps{Name: "d", Disabled: true}),
+	)
+	for _, selector := range []string{"input[name=a]", "textarea[name=b]", "select[name=c]", "input[name=d]"} {
+		if control := h.Query(selector); control == nil || !has(control, "disabled") {
+			t.Fatalf("%s not disabled in:\n%s", selector, h.HTML())
+		}
+	}
+}
+
+func TestSelect_PlaceholderAndChange(t *testing.T) {
+	var chosen string
+	sel := form.NewSelect(form.SelectProps{
+		Name:        "role",
+		Label:       "Role",
+		Placeholder: "Choose a role",
+		Options: []form.SelectOption{
+			{Value: "user", Label: "User"},
+			{Value: "admin", Label: "Admin"},
+		},
+		OnChange: func(value string) { chosen = value },
+	})
+	h := gofetest.Mount(t, sel)
+
+	options := h.QueryAll("select option")
+	if len(options) != 3 || options[0].TextContent() != "Choose a role" || !has(options[0], "selected") {
+		t.Fatalf("placeholder not rendered first and selected in:\n%s", h.HTML())
+	}
+	control := h.Query("select[name=role]")
+	h.Input(attr(control, "id"), "admin")
+	if chosen != "admin" || sel.Value() != "admin" {
+		t.Fatalf("chosen %q, value %q", chosen, sel.Value())
+	}
+	if selected := h.Query("option[selected]"); selected == nil || attr(selected, "value") != "admin" {
+		t.Fatalf("admin not selected in:\n%s", h.HTML())
+	}
+}
+
+func TestCheckbox_Toggle(t *testing.T) {
+	var changes []bool
+	checkbox := form.NewCheckbox(form.CheckboxProps{
+		Name:     "terms",
+		Label:    "I agree to the terms",
+		OnChange: func(checked bool) { changes = append(changes, checked) },
+	})
+	h := gofetest.Mount(t, checkbox)
+
+	id := attr(h.Query("input[type=checkbox]"), "id")
+	h.Click(id)
+	h.Click(id)
+	h.Click(id)
+	if !reflect.DeepEqual(changes, []bool{true, false, true}) || !checkbox.Checked() {
+		t.Fatalf("changes %v, checked %v", changes, checkbox.Checked())
+	}
+	if !has(h.Element(id), "checked") {
+		t.Fatalf("checkbox not rendered checked in:\n%s", h.HTML())
+	}
+}
+
+func TestRadio_GroupAndChange(t *testing.T) {
+	var chosen string
+	radio := form.NewRadio(form.RadioProps{
+		Name:  "size",
+		Label: "Size",
+		Options: []form.RadioOption{
+			{Value: "s", Label: "Small"},
+			{Value: "m", Label: "Medium"},
+			{Value: "l", Label: "Large"},
+		},
+		Value:    "m",
+		OnChange: func(value string) { chosen = value },
+	})
+	h := gofetest.Mount(t, radio)
+
+	group := h.Query("fieldset[role=radiogroup]")
+	if group == nil || h.Query("fieldset legend").TextContent() != "Size" {
+		t.Fatalf("no labelled radio group in:\n%s", h.HTML())
+	}
+	if checked := h.QueryAll("input[checked]"); len(checked) != 1 || attr(checked[0], "value") != "m" {
+		t.Fatalf("initial value not checked in:\n%s", h.HTML())
+	}
+
+	h.ClickElement(h.Query("input[value=l]"))
+	if chosen != "l" || radio.Value() != "l" {
+		t.Fatalf("chosen %q, value %q", chosen, radio.Value())
+	}
+	if checked := h.QueryAll("input[checked]"); len(checked) != 1 || attr(checked[0], "value") != "l" {
+		t.Fatalf("choice not rendered in:\n%s", h.HTML())
+	}
+
+	radio.SetError("Pick a size")
+	goFE.Flush()
+	if group = h.Query("fieldset"); attr(group, "aria-invalid") != "true" {
+		t.Fatalf("group not marked invalid in:\n%s", h.HTML())
+	}
+}
+
+func TestTextarea_RowsAndValue(t *testing.T) {
+	textarea := form.NewTextarea(form.TextareaProps{Name: "message", Value: "Hi", Rows: 4, MaxLength: 500})
+	h := gofetest.Mount(t, textarea)
+
+	control := h.Query("textarea")
+	if attr(control, "rows") != "4" || attr(control, "maxlength") != "500" || control.TextContent() != "Hi" {
+		t.Fatalf("props not rendered in:\n%s", h.HTML())
+	}
+	h.Input(attr(control, "id"), "Hello there")
+	if textarea.Value() != "Hello there" {
+		t.Fatalf("value %q", textarea.Value())
+	}
+}
+
+func TestForm_SubmitCollectsFields(t *testing.T) {
+	submitted := make(chan map[string]interface{}, 1)
+	name := form.NewInput(form.InputProps{Name: "name", Label: "Name"})
+	terms := form.NewCheckbox(form.CheckboxProps{Name: "terms", Label: "I agree"})
+	role := form.NewSelect(form.SelectProps{Name: "role", Value: "user", Options: []form.SelectOption{{Value: "user", Label: "User"}}})
+	f := form.NewForm(form.FormProps{
+		Children:    []goFE.Component{name, terms, role},
+		SubmitLabel: "Sign up",
+		OnSubmit:    func(data map[string]interface{}) { submitted <- data },
+	})
+	h := gofetest.Mount(t, f)
+
+	h.Input(attr(h.Query("input[name=name]"), "id"), "Ada")
+	h.Click(attr(h.Query("input[name=terms]"), "id"))
+	button := h.Query("form button[type=submit]")
+	if button == nil || button.TextContent() != "Sign up" {
+		t.Fatalf("no submit button in:\n%s", h.HTML())
+	}
+	if event := h.ClickElement(button); event.DefaultPrevented() {
+		t.Fatal("the click on the submit button should not be prevented")
+	}
+	want := map[string]interface{}{"name": "Ada", "terms": true, "role": "user"}
+	select {
+	case got := <-submitted:
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("submitted %v, want %v", got, want)
+		}
+	case <-time.After(gofetest.WaitTimeout):
+		t.Fatal("OnSubmit was not called")
+	}
+}
@@ -0,0 +1,95 @@
+package form
+
+import (
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/html"
+)
+
+// InputProps configures an Input.
+type InputProps struct {
+	// Type is the input type, such as "email"; "" means "text".
+	Type        string
+	Name        string
+	Label       string
+	Placeholder string
+	Value       string
+	Required    bool
+	Disabled    bool
+	// Error is shown under the input, which is marked invalid meanwhile.
+	Error     string
+	ClassName string
+	// OnChange is called with the new value as the user types.
+	OnChange func(value string)
+	OnFocus  func()
+	OnBlur   func()
+}
+
+// Input is a labelled single-line text input.
+type Input struct {
+	field
+	props    InputProps
+	value    *goFE.State[string]
+	setValue func(*string)
+}
+
+// NewInput creates an input.
+func NewInput(props InputProps) *Input {
+	i := &Input{props: props}
+	i.init(i, props.Name, props.Error)
+	if i.props.Type == "" {
+		i.props.Type = "text"
+	}
+	value := props.Value
+	i.value, i.setValue = goFE.NewState[string](i, &value)
+	return i
+}
+
+func (i *Input) Render() string {
+	return i.events.Render(i.id,
+		html.Div(html.ID(i.id), html.Class("gofe-field"),
+			i.label(i.props.Label),
+			html.Input(html.ID(i.controlID), html.Type(i.props.Type), html.Value(i.Value()),
+				html.If(i.props.Name != "", html.Name(i.props.Name)),
+				html.If(i.props.Placeholder != "", html.Placeholder(i.props.Placeholder)),
+				html.If(i.props.ClassName != "", html.Class(i.props.ClassName)),
+				html.Required(i.props.Required),
+				html.Disabled(i.props.Disabled),
+				i.invalid(),
+				html.OnInput(func(event dom.Event) {
+					value := event.Value()
+					i.setValue(&value)
+					if i.props.OnChange != nil {
+						i.props.OnChange(value)
+					}
+				}),
+				html.On("focus", func(dom.Event) {
+					if i.props.OnFocus != nil {
+						i.props.OnFocus()
+					}
+				}),
+				html.On("blur", func(dom.Event) {
+					if i.props.OnBlur != nil {
+						i.props.OnBlur()
+					}
+				}),
+			),
+			i.errorMessage(),
+		),
+	)
+}
+
+// Value returns the current value.
+func (i *Input) Value() string {
+	return *i.value.Get()
+}
+
+// SetValue replaces the value, without calling OnChange.
+func (i *Input) SetValue(value string) {
+	i.setValue(&value)
+}
+
+// FieldValue implements Field.
+func (i *Input) FieldValue() interface{} {
+	return i.Value()
+}
@@ -0,0 +1,95 @@
+package form
+
+import (
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/html"
+)
+
+// RadioOption is a choice of a Radio group.
+type RadioOption struct {
+	Value    string
+	Label    string
+	Disabled bool
+}
+
+// RadioProps configures a Radio group.
+type RadioProps struct {
+	// Name groups the radio buttons; "" uses the component's id.
+	Name string
+	// Label is the legend of the group.
+	Label   string
+	Options []RadioOption
+	Value   string
+	// Required makes an option have to be chosen to submit.
+	Required bool
+	Disabled bool
+	// Error is shown under the group, which is marked invalid meanwhile.
+	Error     string
+	ClassName string
+	// OnChange is called with the value of the chosen option.
+	OnChange func(value string)
+}
+
+// Radio is a group of radio buttons in a fieldset, one of which can be
+// chosen.
+type Radio struct {
+	field
+	props    RadioProps
+	value    *goFE.State[string]
+	setValue func(*string)
+}
+
+// NewRadio creates a radio group.
+func NewRadio(props RadioProps) *Radio {
+	r := &Radio{props: props}
+	r.init(r, props.Name, props.Error)
+	value := props.Value
+	r.value, r.setValue = goFE.NewState[string](r, &value)
+	return r
+}
+
+func (r *Radio) Render() string {
+	return r.events.Render(r.id,
+		html.El("fieldset", html.ID(r.id), html.Class("gofe-field gofe-field-radio"), html.Role("radiogroup"),
+			html.If(r.props.ClassName != "", html.Class(r.props.ClassName)),
+			html.Disabled(r.props.Disabled),
+			r.invalid(),
+			html.If(r.props.Label != "", html.El("legend", html.Text(r.props.Label))),
+			html.Map(r.props.Options, func(option RadioOption) html.Node {
+				return html.Label(
+					html.Input(html.Type("radio"), html.Name(r.FieldName()), html.Value(option.Value),
+						html.Checked(option.Value == r.Value()),
+						html.Required(r.props.Required),
+						html.Disabled(option.Disabled),
+						html.On("change", func(dom.Event) {
+							value := option.Value
+							r.setValue(&value)
+							if r.props.OnChange != nil {
+								r.props.OnChange(value)
+							}
+						}),
+					),
+					html.Text(" "+option.Label),
+				)
+			}),
+			r.errorMessage(),
+		),
+	)
+}
+
+// Value returns the value of the chosen option, or "" if none is.
+func (r *Radio) Value() string {
+	return *r.value.Get()
+}
+
+// SetValue chooses the option with the given value, without calling
+// OnChange.
+func (r *Radio) SetValue(value string) {
+	r.setValue(&value)
+}
+
+// FieldValue implements Field.
+func (r *Radio) FieldValue() interface{} {
+	return r.Value()
+}
@@ -0,0 +1,95 @@
+package form
+
+import (
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/html"
+)
+
+// SelectOption is a choice of a Select.
+type SelectOption struct {
+	Value    string
+	Label    string
+	Disabled bool
+}
+
+// SelectProps configures a Select.
+type SelectProps struct {
+	Name    string
+	Label   string
+	Options []SelectOption
+	Value   string
+	// Placeholder is shown as an empty, unselectable first option while no
+	// option is chosen.
+	Placeholder string
+	Required    bool
+	Disabled    bool
+	// Error is shown under the select, which is marked invalid meanwhile.
+	Error     string
+	ClassName string
+	// OnChange is called with the value of the chosen option.
+	OnChange func(value string)
+}
+
+// Select is a labelled drop-down list.
+type Select struct {
+	field
+	props    SelectProps
+	value    *goFE.State[string]
+	setValue func(*string)
+}
+
+// NewSelect creates a select.
+func NewSelect(props SelectProps) *Select {
+	s := &Select{props: props}
+	s.init(s, props.Name, props.Error)
+	value := props.Value
+	s.value, s.setValue = goFE.NewState[string](s, &value)
+	return s
+}
+
+func (s *Select) Render() string {
+	return s.events.Render(s.id,
+		html.Div(html.ID(s.id), html.Class("gofe-field"),
+			s.label(s.props.Label),
+			html.Select(html.ID(s.controlID),
+				html.If(s.props.Name != "", html.Name(s.props.Name)),
+				html.If(s.props.ClassName != "", html.Class(s.props.ClassName)),
+				html.Required(s.props.Required),
+				html.Disabled(s.props.Disabled),
+				s.invalid(),
+				html.On("change", func(event dom.Event) {
+					value := event.Value()
+					s.setValue(&value)
+					if s.props.OnChange != nil {
+						s.props.OnChange(value)
+					}
+				}),
+				html.If(s.props.Placeholder != "",
+					html.Option(html.Value(""), html.Disabled(true), html.Selected(s.Value() == ""), html.Text(s.props.Placeholder)),
+				),
+				html.Map(s.props.Options, func(option SelectOption) html.Node {
+					return html.Option(html.Value(option.Value), html.Selected(option.Value == s.Value()),
+						html.Disabled(option.Disabled), html.Text(option.Label))
+				}),
+			),
+			s.errorMessage(),
+		),
+	)
+}
+
+// Value returns the value of the chosen option, or "" if none is.
+func (s *Select) Value() string {
+	return *s.value.Get()
+}
+
+// SetValue chooses the option with the given value, without calling
+// OnChange.
+func (s *Select) SetValue(value string) {
+	s.setValue(&value)
+}
+
+// FieldValue implements Field.
+func (s *Select) FieldValue() interface{} {
+	return s.Value()
+}
@@ -0,0 +1,87 @@
+package form
+
+import (
+	"strconv"
+
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/html"
+)
+
+// TextareaProps configures a Textarea.
+type TextareaProps struct {
+	Name        string
+	Label       string
+	Placeholder string
+	Value       string
+	// Rows is the visible number of lines; 0 leaves the browser default.
+	Rows int
+	// MaxLength limits the number of characters; 0 means no limit.
+	MaxLength int
+	Required  bool
+	Disabled  bool
+	// Error is shown under the textarea, which is marked invalid meanwhile.
+	Error     string
+	ClassName string
+	// OnChange is called with the new value as the user types.
+	OnChange func(value string)
+}
+
+// Textarea is a labelled multi-line text input.
+type Textarea struct {
+	field
+	props    TextareaProps
+	value    *goFE.State[string]
+	setValue func(*string)
+}
+
+// NewTextarea creates a textarea.
+func NewTextarea(props TextareaProps) *Textarea {
+	t := &Textarea{props: props}
+	t.init(t, props.Name, props.Error)
+	value := props.Value
+	t.value, t.setValue = goFE.NewState[string](t, &value)
+	return t
+}
+
+func (t *Textarea) Render() string {
+	return t.events.Render(t.id,
+		html.Div(html.ID(t.id), html.Class("gofe-field"),
+			t.label(t.props.Label),
+			html.Textarea(html.ID(t.controlID),
+				html.If(t.props.Name != "", html.Name(t.props.Name)),
+				html.If(t.props.Placeholder != "", html.Placeholder(t.props.Placeholder)),
+				html.If(t.props.Rows > 0, html.Attr("rows", strconv.Itoa(t.props.Rows))),
+				html.If(t.props.MaxLength > 0, html.Attr("maxlength", strconv.Itoa(t.props.MaxLength))),
+				html.If(t.props.ClassName != "", html.Class(t.props.ClassName)),
+				html.Required(t.props.Required),
+				html.Disabled(t.props.Disabled),
+				t.invalid(),
+				html.OnInput(func(event dom.Event) {
+					value := event.Value()
+					t.setValue(&value)
+					if t.props.OnChange != nil {
+						t.props.OnChange(value)
+					}
+				}),
+				html.Text(t.Value()),
+			),
+			t.errorMessage(),
+		),
+	)
+}
+
+// Value returns the current value.
+func (t *Textarea) Value() string {
+	return *t.value.Get()
+}
+
+// SetValue replaces the value, without calling OnChange.
+func (t *Textarea) SetValue(value string) {
+	t.setValue(&value)
+}
+
+// FieldValue implements Field.
+func (t *Textarea) FieldValue() interface{} {
+	return t.Value()
+}
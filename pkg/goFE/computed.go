@@ -0,0 +1,227 @@
+package goFE
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// dependency is something a Computed can read and be invalidated by: a State
+// or another Computed.
+type dependency interface {
+	dependencyID() uuid.UUID
+	addDependent(id uuid.UUID, invalidate func())
+	removeDependent(id uuid.UUID)
+}
+
+var trackingLock sync.Mutex
+
+// trackingFrame collects the dependencies of a Computed being recomputed.
+type trackingFrame struct {
+	id         uuid.UUID
+	invalidate func()
+	deps       map[uuid.UUID]dependency
+}
+
+// tracking holds, per goroutine, the stack of frames of the Computed values
+// that goroutine is recomputing, innermost last. Reads made on any other
+// goroutine meanwhile are not dependencies of those Computed values.
+var tracking = make(map[uint64][]*trackingFrame)
+
+// pushFrame starts tracking the reads the calling goroutine makes for frame.
+// It returns the goroutine's id, to be handed to popFrame.
+func pushFrame(frame *trackingFrame) uint64 {
+	id := goroutineID()
+	trackingLock.Lock()
+	defer trackingLock.Unlock()
+	tracking[id] = append(tracking[id], frame)
+	return id
+}
+
+// popFrame stops tracking the innermost frame of goroutine id.
+func popFrame(id uint64) {
+	trackingLock.Lock()
+	defer trackingLock.Unlock()
+	frames := tracking[id][:len(tracking[id])-1]
+	if len(frames) == 0 {
+		delete(tracking, id)
+		return
+	}
+	tracking[id] = frames
+}
+
+// track records dep as a dependency of the innermost Computed the calling
+// goroutine is recomputing, if any. The Computed subscribes to dep straight
+// away, so a change made while it is still computing invalidates it.
+func track(dep dependency) {
+	trackingLock.Lock()
+	idle := len(tracking) == 0
+	trackingLock.Unlock()
+	if idle {
+		return
+	}
+	id := goroutineID()
+	trackingLock.Lock()
+	defer trackingLock.Unlock()
+	frames := tracking[id]
+	if len(frames) == 0 {
+		return
+	}
+	frame := frames[len(frames)-1]
+	if _, ok := frame.deps[dep.dependencyID()]; !ok {
+		frame.deps[dep.dependencyID()] = dep
+		dep.addDependent(frame.id, frame.invalidate)
+	}
+}
+
+// Computed is a value derived from States (or other Computed values) read
+// with Get. It is recomputed lazily, the first time it is read after one of
+// those changes, and memoized otherwise. A change invalidates it once however
+// many of its dependencies were updated in the same flush, re-rendering its
+// owner and running its effects a single time.
+type Computed[T any] struct {
+	id      uuid.UUID
+	owner   Component
+	compute func() *T
+
+	lock  sync.Mutex
+	value *T
+	stale bool
+	// generation is bumped by every invalidation, so a recomputation that
+	// was overtaken by one is not memoized.
+	generation int
+	computing  bool
+	killed     bool
+	deps       map[uuid.UUID]dependency
+	effects    []func(value *T)
+	dependents map[uuid.UUID]func()
+}
+
+// NewComputed creates a value owned by component and derived by compute.
+// Every State or Computed that compute reads through Get becomes a
+// dependency; they are tracked again on each recomputation, so conditional
+// reads are handled.
+func NewComputed[T any](component Component, compute func() *T) *Computed[T] {
+	computed := &Computed[T]{
+		id:         uuid.New(),
+		owner:      component,
+		compute:    compute,
+		stale:      true,
+		deps:       make(map[uuid.UUID]dependency),
+		dependents: make(map[uuid.UUID]func()),
+	}
+	onTeardown(component, computed.kill)
+	return computed
+}
+
+// Get returns the derived value, recomputing it if a dependency changed
+// since it was last read.
+func (c *Computed[T]) Get() *T {
+	track(c)
+	c.lock.Lock()
+	if !c.stale {
+		defer c.lock.Unlock()
+		return c.value
+	}
+	generation := c.generation
+	c.computing = true
+	c.lock.Unlock()
+
+	frame := &trackingFrame{id: c.id, invalidate: c.invalidate, deps: make(map[uuid.UUID]dependency)}
+	goroutine := pushFrame(frame)
+	value := c.compute()
+	popFrame(goroutine)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.computing = false
+	if c.killed {
+		for _, dep := range frame.deps {
+			dep.removeDependent(c.id)
+		}
+		return value
+	}
+	for id, dep := range c.deps {
+		if _, ok := frame.deps[id]; !ok {
+			dep.removeDependent(c.id)
+		}
+	}
+	c.deps = frame.deps
+	if c.generation == generation {
+		c.value = value
+		c.stale = false
+	}
+	return value
+}
+
+// AddEffect adds a function called with the new value, on the scheduler,
+// whenever a dependency changes.
+func (c *Computed[T]) AddEffect(effect func(value *T)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.effects = append(c.effects, effect)
+}
+
+// invalidate marks the value stale after a dependency changed. It runs on
+// the scheduler.
+func (c *Computed[T]) invalidate() {
+	c.lock.Lock()
+	if c.killed {
+		c.lock.Unlock()
+		return
+	}
+	c.generation++
+	if c.stale && !c.computing {
+		c.lock.Unlock()
+		return
+	}
+	c.stale = true
+	effects := append([]func(value *T){}, c.effects...)
+	dependents := make([]func(), 0, len(c.dependents))
+	for _, invalidate := range c.dependents {
+		dependents = append(dependents, invalidate)
+	}
+	c.lock.Unlock()
+
+	sched.markDirty(c.owner)
+	for _, invalidate := range dependents {
+		invalidate()
+	}
+	for _, effect := range effects {
+		effect := effect
+		sched.queueEffect(c.owner, func() {
+			effect(c.Get())
+		})
+	}
+}
+
+func (c *Computed[T]) kill() {
+	c.lock.Lock()
+	deps := c.deps
+	c.killed = true
+	c.deps = nil
+	c.effects = nil
+	c.dependents = nil
+	c.lock.Unlock()
+	for _, dep := range deps {
+		dep.removeDependent(c.id)
+	}
+}
+
+func (c *Computed[T]) dependencyID() uuid.UUID {
+	return c.id
+}
+
+func (c *Computed[T]) addDependent(id uuid.UUID, invalidate func()) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.dependents != nil {
+		c.dependents[id] = invalidate
+	}
+}
+
+func (c *Computed[T]) removeDependent(id uuid.UUID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.dependents, id)
+}
@@ -0,0 +1,115 @@
+package goFE
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+func TestComputed_TracksAndMemoizes(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	price, setPrice := NewState[int](owner, new(int))
+	quantity, setQuantity := NewState[int](owner, new(int))
+	var computes, effects int
+	total := NewComputed(owner, func() *int {
+		computes++
+		value := *price.Get() * *quantity.Get()
+		return &value
+	})
+	label := NewComputed(owner, func() *string {
+		value := "total " + strconv.Itoa(*total.Get())
+		return &value
+	})
+	label.AddEffect(func(*string) { effects++ })
+	owner.value = func() string { return *label.Get() }
+
+	memory := dom.NewMemory()
+	SetDocument(NewDocumentWithDOM([]Component{owner}, memory))
+	defer SetDocument(nil)
+	document.Init()
+
+	two, three := 2, 3
+	Batch(func() {
+		setPrice(&two)
+		setQuantity(&three)
+	})
+	Flush()
+
+	if got := memory.GetElementByID(owner.id.String()).TextContent(); got != "total 6" {
+		t.Fatalf("rendered %q", got)
+	}
+	if computes != 2 || effects != 1 || owner.renders != 2 {
+		t.Fatalf("computes = %d, effects = %d, renders = %d; want 2, 1, 2", computes, effects, owner.renders)
+	}
+	label.Get()
+	if computes != 2 {
+		t.Fatal("unchanged value was recomputed")
+	}
+
+	killAllStates(owner)
+	if len(price.dependents) != 0 || len(total.dependents) != 0 {
+		t.Fatal("computed values still subscribed after teardown")
+	}
+}
+
+func TestComputed_InvalidatedWhileComputing(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	price, _ := NewState[int](owner, new(int))
+	defer killAllStates(owner)
+	changed := false
+	double := NewComputed(owner, func() *int {
+		value := *price.Get() * 2
+		if !changed {
+			// The state changes after it was read, as if another goroutine
+			// updated it mid-compute
+			changed = true
+			five := 5
+			price.set(&five)
+		}
+		return &value
+	})
+
+	if got := *double.Get(); got != 0 {
+		t.Fatalf("first Get = %d, want the value computed from the state it read", got)
+	}
+	if got := *double.Get(); got != 10 {
+		t.Fatalf("Get after a change mid-compute = %d, want 10", got)
+	}
+	if got := *double.Get(); got != 10 {
+		t.Fatalf("memoized Get = %d, want 10", got)
+	}
+}
+
+func TestComputed_IgnoresReadsFromOtherGoroutines(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	price, _ := NewState[int](owner, new(int))
+	unrelated, _ := NewState[int](owner, new(int))
+	defer killAllStates(owner)
+	computes := 0
+	double := NewComputed(owner, func() *int {
+		computes++
+		// Another goroutine reads a state while this one is computing
+		done := make(chan struct{})
+		go func() {
+			unrelated.Get()
+			close(done)
+		}()
+		<-done
+		value := *price.Get() * 2
+		return &value
+	})
+
+	double.Get()
+	seven := 7
+	unrelated.set(&seven)
+	double.Get()
+	if computes != 1 {
+		t.Fatalf("computed %d times, want 1: a read on another goroutine became a dependency", computes)
+	}
+	price.set(&seven)
+	if got := *double.Get(); got != 14 || computes != 2 {
+		t.Fatalf("Get after a dependency changed = %d (%d computes), want 14 (2)", got, computes)
+	}
+}
@@ -0,0 +1,164 @@
+package goFE
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Context carries a value of type T down the component tree without passing
+// it through every constructor. A Provider supplies the value to its
+// subtree, and descendants read it with Use.
+type Context[T any] struct {
+	defaultValue *T
+
+	lock sync.Mutex
+	// providers caches the provider each consumer found.
+	providers map[uuid.UUID]*Provider[T]
+}
+
+// NewContext creates a context whose consumers see defaultValue when they
+// have no Provider above them.
+func NewContext[T any](defaultValue *T) *Context[T] {
+	return &Context[T]{
+		defaultValue: defaultValue,
+		providers:    make(map[uuid.UUID]*Provider[T]),
+	}
+}
+
+// Provider is a component that supplies the value of a State to every
+// descendant using its context. Consumers are re-rendered when the state
+// changes. It renders its children without any markup of its own.
+type Provider[T any] struct {
+	id       uuid.UUID
+	context  *Context[T]
+	state    *State[T]
+	children []Component
+
+	lock sync.Mutex
+	// consumers are the components that read the value through Use.
+	consumers map[uuid.UUID]Component
+}
+
+// NewProvider creates a provider of state for context, wrapping children.
+func NewProvider[T any](context *Context[T], state *State[T], children ...Component) *Provider[T] {
+	provider := &Provider[T]{
+		id:        uuid.New(),
+		context:   context,
+		state:     state,
+		children:  children,
+		consumers: make(map[uuid.UUID]Component),
+	}
+	state.AddEffect(func(*T) {
+		provider.lock.Lock()
+		defer provider.lock.Unlock()
+		for _, consumer := range provider.consumers {
+			sched.markDirty(consumer)
+		}
+	})
+	return provider
+}
+
+func (p *Provider[T]) Render() string {
+	return RenderChildren(p)
+}
+
+func (p *Provider[T]) GetID() uuid.UUID {
+	return p.id
+}
+
+func (p *Provider[T]) GetChildren() []Component {
+	return p.children
+}
+
+func (p *Provider[T]) InitEventListeners() {}
+
+// Use returns the value provided to consumer by its closest Provider of c,
+// or the default value if there is none, and subscribes consumer to changes
+// of that value until it is torn down. Call it from Render, once consumer
+// is part of the document's component tree.
+func (c *Context[T]) Use(consumer Component) *T {
+	id := consumer.GetID()
+	c.lock.Lock()
+	provider, ok := c.providers[id]
+	c.lock.Unlock()
+	if !ok {
+		provider = c.find(consumer)
+		if provider == nil {
+			return c.defaultValue
+		}
+		c.subscribe(consumer, provider)
+	}
+	return provider.state.Value
+}
+
+// find walks down from the roots of the tree to consumer and returns the
+// closest Provider of c above it.
+func (c *Context[T]) find(consumer Component) *Provider[T] {
+	path := pathTo(rootsOf(consumer.GetID()), consumer.GetID())
+	for i := len(path) - 2; i >= 0; i-- {
+		if provider, ok := path[i].(*Provider[T]); ok && provider.context == c {
+			return provider
+		}
+	}
+	return nil
+}
+
+func (c *Context[T]) subscribe(consumer Component, provider *Provider[T]) {
+	id := consumer.GetID()
+	c.lock.Lock()
+	c.providers[id] = provider
+	c.lock.Unlock()
+	provider.lock.Lock()
+	provider.consumers[id] = consumer
+	provider.lock.Unlock()
+	onTeardown(consumer, func() {
+		c.lock.Lock()
+		delete(c.providers, id)
+		c.lock.Unlock()
+		provider.lock.Lock()
+		delete(provider.consumers, id)
+		provider.lock.Unlock()
+	})
+}
+
+// pathTo returns the components from one of roots down to the component
+// with the given id, or nil if it is not in the tree.
+func pathTo(roots []Component, id uuid.UUID) []Component {
+	for _, root := range roots {
+		if root.GetID() == id {
+			return []Component{root}
+		}
+		if path := pathTo(root.GetChildren(), id); path != nil {
+			return append([]Component{root}, path...)
+		}
+	}
+	return nil
+}
+
+// treeRoots returns the top-level components of every mounted document.
+func treeRoots() []Component {
+	var roots []Component
+	for _, d := range documents() {
+		roots = append(roots, d.GetComponentTree()...)
+	}
+	return roots
+}
+
+// rootsOf returns the top-level components of the tree holding the
+// component with the given id: the one a RenderToString call is rendering,
+// or else those of every mounted document.
+func rootsOf(id uuid.UUID) []Component {
+	serverTreesLock.Lock()
+	trees := make([]*serverTree, 0, len(serverTrees))
+	for tree := range serverTrees {
+		trees = append(trees, tree)
+	}
+	serverTreesLock.Unlock()
+	for _, tree := range trees {
+		if pathTo(tree.roots, id) != nil {
+			return tree.roots
+		}
+	}
+	return treeRoots()
+}
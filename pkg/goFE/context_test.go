@@ -0,0 +1,105 @@
+package goFE
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+type themeConsumer struct {
+	id      uuid.UUID
+	context *Context[string]
+}
+
+func (c *themeConsumer) Render() string {
+	return `<p id="` + c.id.String() + `">` + *c.context.Use(c) + `</p>`
+}
+func (c *themeConsumer) GetID() uuid.UUID         { return c.id }
+func (c *themeConsumer) GetChildren() []Component { return nil }
+func (c *themeConsumer) InitEventListeners()      {}
+
+type stateOwner struct{ id uuid.UUID }
+
+func (o *stateOwner) Render() string           { return `<div id="` + o.id.String() + `"></div>` }
+func (o *stateOwner) GetID() uuid.UUID         { return o.id }
+func (o *stateOwner) GetChildren() []Component { return nil }
+func (o *stateOwner) InitEventListeners()      {}
+
+func TestContext_ProviderUpdatesConsumers(t *testing.T) {
+	fallback := "light"
+	theme := NewContext[string](&fallback)
+	owner := &stateOwner{id: uuid.New()}
+	dark := "dark"
+	state, setTheme := NewState[string](owner, &dark)
+	inside := &themeConsumer{id: uuid.New(), context: theme}
+	outside := &themeConsumer{id: uuid.New(), context: theme}
+	provider := NewProvider(theme, state, NewSwappableComponent(inside))
+
+	memory := dom.NewMemory()
+	SetDocument(NewDocumentWithDOM([]Component{owner, provider, outside}, memory))
+	defer SetDocument(nil)
+	document.Init()
+
+	text := func(c *themeConsumer) string {
+		return memory.GetElementByID(c.id.String()).TextContent()
+	}
+	if text(inside) != "dark" || text(outside) != "light" {
+		t.Fatalf("initial values = %q, %q", text(inside), text(outside))
+	}
+
+	solarized := "solarized"
+	setTheme(&solarized)
+	Flush()
+	if text(inside) != "solarized" || text(outside) != "light" {
+		t.Fatalf("values after update = %q, %q", text(inside), text(outside))
+	}
+
+	killAllStates(provider)
+	if len(provider.consumers) != 0 || len(theme.providers) != 0 {
+		t.Fatal("consumer still subscribed after teardown")
+	}
+}
+
+func TestContext_ServerRender(t *testing.T) {
+	theme := NewContext[string](nil)
+	owner := &stateOwner{id: uuid.New()}
+	dark := "dark"
+	state, _ := NewState[string](owner, &dark)
+	consumer := &themeConsumer{id: uuid.New(), context: theme}
+
+	out, err := RenderToString(owner, NewProvider(theme, state, consumer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<div id="` + owner.id.String() + `"></div><p id="` + consumer.id.String() + `">dark</p>`
+	if out.HTML != want {
+		t.Fatalf("HTML = %s", out.HTML)
+	}
+}
+
+func TestContext_ConcurrentServerRenders(t *testing.T) {
+	theme := NewContext[string](nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(value string) {
+			defer wg.Done()
+			owner := &stateOwner{id: uuid.New()}
+			state, _ := NewState[string](owner, &value)
+			consumer := &themeConsumer{id: uuid.New(), context: theme}
+			out, err := RenderToString(owner, NewProvider(theme, state, consumer))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if want := `">` + value + `</p>`; !strings.HasSuffix(out.HTML, want) {
+				t.Errorf("render for %s = %s", value, out.HTML)
+			}
+		}("theme-" + strconv.Itoa(i))
+	}
+	wg.Wait()
+}
@@ -0,0 +1,192 @@
+package goFE
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// highlightStyle is added to the style of the element HighlightComponent
+// points at.
+const highlightStyle = "outline: 2px solid #4f8cff; outline-offset: -2px; background-color: rgba(79, 140, 255, 0.15);"
+
+var devtoolsLock sync.Mutex
+
+// devtoolsEnabled turns on the bookkeeping behind InspectTree.
+var devtoolsEnabled bool
+
+// renderStats holds the render count and last render duration of each live
+// component, while devtools are enabled.
+var renderStats = make(map[uuid.UUID]*renderStat)
+
+type renderStat struct {
+	count int
+	last  time.Duration
+}
+
+// highlighted is the element HighlightComponent last outlined, with the
+// declarations it added to its style.
+var highlighted struct {
+	id       string
+	added    string
+	hadStyle bool
+	document *Document
+}
+
+// EnableDevtools starts recording render counts and durations for
+// InspectTree, and in the browser exposes window.__GOFE_DEVTOOLS__ with:
+//
+//	tree()            the live component tree, as returned by InspectTree
+//	highlight(id)     outlines the DOM region of the component with that id
+//	clearHighlight()  removes the outline
+//
+// Call it before mounting so first renders are counted too. Add a
+// DevtoolsOverlay to the tree for an in-page view of the same data.
+func EnableDevtools() {
+	devtoolsLock.Lock()
+	devtoolsEnabled = true
+	devtoolsLock.Unlock()
+	exposeDevtools()
+}
+
+// renderComponent renders component, recording how long it took when
+// devtools are enabled. The duration includes rendering its children.
+func renderComponent(component Component) string {
+	devtoolsLock.Lock()
+	enabled := devtoolsEnabled
+	devtoolsLock.Unlock()
+	if !enabled {
+		return component.Render()
+	}
+	start := time.Now()
+	html := component.Render()
+	elapsed := time.Since(start)
+
+	devtoolsLock.Lock()
+	defer devtoolsLock.Unlock()
+	stat, ok := renderStats[component.GetID()]
+	if !ok {
+		stat = &renderStat{}
+		renderStats[component.GetID()] = stat
+	}
+	stat.count++
+	stat.last = elapsed
+	return html
+}
+
+// forgetRenderStats drops the statistics of a component torn down.
+func forgetRenderStats(id uuid.UUID) {
+	devtoolsLock.Lock()
+	defer devtoolsLock.Unlock()
+	delete(renderStats, id)
+}
+
+// ComponentInfo describes a live component for devtools.
+type ComponentInfo struct {
+	ID string `json:"id"`
+	// Type is the component's Go type, such as "*counter.Counter".
+	Type string `json:"type"`
+	// States holds the values of the component's states as JSON, in the
+	// order they were created.
+	States []json.RawMessage `json:"states"`
+	// Renders counts the renders since devtools were enabled, and
+	// LastRender is how long the last one took, children included.
+	Renders    int             `json:"renders"`
+	LastRender time.Duration   `json:"lastRenderNs"`
+	Children   []ComponentInfo `json:"children,omitempty"`
+}
+
+// InspectTree describes the live component tree of every mounted document.
+// DevtoolsOverlay components are left out.
+func InspectTree() []ComponentInfo {
+	return inspect(treeRoots())
+}
+
+func inspect(components []Component) []ComponentInfo {
+	infos := make([]ComponentInfo, 0, len(components))
+	for _, component := range components {
+		if _, ok := component.(*DevtoolsOverlay); ok {
+			continue
+		}
+		info := ComponentInfo{
+			ID:       component.GetID().String(),
+			Type:     reflect.TypeOf(component).String(),
+			States:   []json.RawMessage{},
+			Children: inspect(component.GetChildren()),
+		}
+		registryLock.Lock()
+		states := append([]stateHandle{}, componentStates[component.GetID()]...)
+		registryLock.Unlock()
+		for _, state := range states {
+			data, err := state.marshal()
+			if err != nil {
+				data, _ = json.Marshal(map[string]string{"error": err.Error()})
+			}
+			info.States = append(info.States, data)
+		}
+		devtoolsLock.Lock()
+		if stat, ok := renderStats[component.GetID()]; ok {
+			info.Renders, info.LastRender = stat.count, stat.last
+		}
+		devtoolsLock.Unlock()
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// HighlightComponent outlines the DOM region of the component with the given
+// id, removing any previous highlight. It reports whether the component's
+// element was found.
+func HighlightComponent(id string) bool {
+	ClearHighlight()
+	for _, d := range documents() {
+		element := d.dom.GetElementByID(id)
+		if element == nil {
+			continue
+		}
+		style, hadStyle := element.GetAttribute("style")
+		added := highlightStyle
+		if style != "" && !strings.HasSuffix(strings.TrimSpace(style), ";") {
+			added = ";" + added
+		}
+		devtoolsLock.Lock()
+		highlighted.id, highlighted.added, highlighted.hadStyle, highlighted.document = id, added, hadStyle, d
+		devtoolsLock.Unlock()
+		element.SetAttribute("style", style+added)
+		return true
+	}
+	return false
+}
+
+// ClearHighlight removes the outline added by HighlightComponent. Only the
+// declarations it added are removed, so style changes rendered meanwhile are
+// kept.
+func ClearHighlight() {
+	devtoolsLock.Lock()
+	previous := highlighted
+	highlighted.document = nil
+	devtoolsLock.Unlock()
+	if previous.document == nil {
+		return
+	}
+	element := previous.document.dom.GetElementByID(previous.id)
+	if element == nil {
+		return
+	}
+	style, _ := element.GetAttribute("style")
+	i := strings.LastIndex(style, previous.added)
+	if i < 0 {
+		// Re-rendered since, which already dropped the highlight
+		return
+	}
+	style = style[:i] + style[i+len(previous.added):]
+	if style == "" && !previous.hadStyle {
+		element.RemoveAttribute("style")
+	} else {
+		element.SetAttribute("style", style)
+	}
+}
@@ -0,0 +1,37 @@
+//go:build js
+
+package goFE
+
+import (
+	"encoding/json"
+	"syscall/js"
+)
+
+// devtoolsGlobal is the window property the devtools API is exposed as.
+const devtoolsGlobal = "__GOFE_DEVTOOLS__"
+
+func exposeDevtools() {
+	if !js.Global().Get(devtoolsGlobal).IsUndefined() {
+		return
+	}
+	api := js.Global().Get("Object").New()
+	// The callbacks live as long as the page, so they are never released
+	api.Set("tree", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		data, err := json.Marshal(InspectTree())
+		if err != nil {
+			return js.Null()
+		}
+		return js.Global().Get("JSON").Call("parse", string(data))
+	}))
+	api.Set("highlight", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if len(args) == 0 {
+			return false
+		}
+		return HighlightComponent(args[0].String())
+	}))
+	api.Set("clearHighlight", js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		ClearHighlight()
+		return nil
+	}))
+	js.Global().Set(devtoolsGlobal, api)
+}
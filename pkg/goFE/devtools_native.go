@@ -0,0 +1,7 @@
+//go:build !js
+
+package goFE
+
+// exposeDevtools has no window to expose the devtools API on outside the
+// browser; use InspectTree and HighlightComponent directly.
+func exposeDevtools() {}
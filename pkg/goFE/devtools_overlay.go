@@ -0,0 +1,99 @@
+package goFE
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+// devtoolsItemPrefix prefixes the ids of the overlay's tree items, followed
+// by the id of the component they describe.
+const devtoolsItemPrefix = "gofe-devtools-"
+
+// DevtoolsOverlay is a panel pinned to the corner of the page listing the
+// live component tree with each component's state, render count and last
+// render duration. Hovering a component highlights its DOM region. The
+// panel is a snapshot: click Refresh to update it.
+type DevtoolsOverlay struct {
+	id        uuid.UUID
+	refreshID uuid.UUID
+	listID    uuid.UUID
+	tree      *State[[]ComponentInfo]
+	setTree   func(*[]ComponentInfo)
+}
+
+// NewDevtoolsOverlay creates the panel; add it to the component tree, after
+// calling EnableDevtools.
+func NewDevtoolsOverlay() *DevtoolsOverlay {
+	overlay := &DevtoolsOverlay{
+		id:        uuid.New(),
+		refreshID: uuid.New(),
+		listID:    uuid.New(),
+	}
+	overlay.tree, overlay.setTree = NewState[[]ComponentInfo](overlay, &[]ComponentInfo{})
+	return overlay
+}
+
+func (o *DevtoolsOverlay) Render() string {
+	return `<aside id="` + o.id.String() + `" style="position: fixed; right: 0; bottom: 0; z-index: 2147483647; ` +
+		`width: 380px; max-height: 40vh; overflow: auto; padding: 8px; background: #1e1e1e; color: #ddd; font: 12px monospace;">` +
+		`<button id="` + o.refreshID.String() + `" type="button">Refresh</button>` +
+		`<ul id="` + o.listID.String() + `" style="margin: 0; padding-left: 12px;">` +
+		renderComponentInfos(*o.tree.Value) + `</ul></aside>`
+}
+
+func renderComponentInfos(infos []ComponentInfo) string {
+	var buffer strings.Builder
+	for _, info := range infos {
+		buffer.WriteString(`<li id="` + devtoolsItemPrefix + info.ID + `"><strong>` + html.EscapeString(info.Type) +
+			`</strong> renders: ` + strconv.Itoa(info.Renders) + `, last: ` + info.LastRender.String())
+		for _, state := range info.States {
+			buffer.WriteString(`<pre style="margin: 0; white-space: pre-wrap;">` + html.EscapeString(string(state)) + `</pre>`)
+		}
+		if len(info.Children) > 0 {
+			buffer.WriteString(`<ul style="margin: 0; padding-left: 12px;">` + renderComponentInfos(info.Children) + `</ul>`)
+		}
+		buffer.WriteString(`</li>`)
+	}
+	return buffer.String()
+}
+
+func (o *DevtoolsOverlay) GetID() uuid.UUID {
+	return o.id
+}
+
+func (o *DevtoolsOverlay) GetChildren() []Component {
+	return nil
+}
+
+func (o *DevtoolsOverlay) InitEventListeners() {
+	doc := GetDocument()
+	doc.On(o.refreshID, "click", func(dom.Event) {
+		o.Refresh()
+	})
+	doc.On(o.listID, "mouseover", func(event dom.Event) {
+		for node := event.Target(); node != nil; node = node.ParentNode() {
+			if id := node.ID(); strings.HasPrefix(id, devtoolsItemPrefix) {
+				HighlightComponent(strings.TrimPrefix(id, devtoolsItemPrefix))
+				return
+			}
+		}
+	})
+	doc.On(o.listID, "mouseleave", func(dom.Event) {
+		ClearHighlight()
+	})
+}
+
+// OnMount takes the first snapshot once the rest of the tree is in the DOM.
+func (o *DevtoolsOverlay) OnMount() {
+	o.Refresh()
+}
+
+// Refresh updates the panel with the current component tree.
+func (o *DevtoolsOverlay) Refresh() {
+	tree := InspectTree()
+	o.setTree(&tree)
+}
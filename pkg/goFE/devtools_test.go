@@ -0,0 +1,74 @@
+package goFE
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+)
+
+func TestDevtools_InspectAndHighlight(t *testing.T) {
+	EnableDevtools()
+	memory := dom.NewMemory()
+	counter := newTestCounter()
+	overlay := NewDevtoolsOverlay()
+	SetDocument(NewDocumentWithDOM([]Component{counter, overlay}, memory))
+	defer SetDocument(nil)
+	document.Init()
+	Flush()
+
+	memory.Dispatch(memory.GetElementByID(counter.buttonID.String()), "click")
+	Flush()
+	tree := InspectTree()
+	if len(tree) != 1 {
+		t.Fatalf("tree = %+v, want the counter without the overlay", tree)
+	}
+	info := tree[0]
+	if info.ID != counter.id.String() || info.Type != "*goFE.testCounter" ||
+		len(info.States) != 1 || string(info.States[0]) != "1" || info.Renders != 2 {
+		t.Fatalf("counter info = %+v", info)
+	}
+
+	overlay.Refresh()
+	Flush()
+	item := memory.GetElementByID(devtoolsItemPrefix + counter.id.String())
+	if item == nil || !strings.Contains(item.TextContent(), "renders: 2") {
+		t.Fatal("overlay does not list the counter")
+	}
+	memory.Dispatch(item.ChildAt(0), "mouseover")
+	element := memory.GetElementByID(counter.id.String())
+	if style, _ := element.GetAttribute("style"); style != highlightStyle {
+		t.Fatalf("hovering did not highlight the counter, style = %q", style)
+	}
+	memory.Dispatch(memory.GetElementByID(overlay.listID.String()), "mouseleave")
+	if _, ok := element.GetAttribute("style"); ok {
+		t.Fatal("leaving the overlay did not clear the highlight")
+	}
+}
+
+func TestDevtools_ClearHighlightKeepsRenderedStyle(t *testing.T) {
+	memory := dom.NewMemory()
+	memory.GetElementByID("root").SetInnerHTML(`<div id="box" style="color: red"></div>`)
+	SetDocument(NewDocumentWithDOM(nil, memory))
+	defer SetDocument(nil)
+	box := memory.GetElementByID("box")
+
+	if !HighlightComponent("box") {
+		t.Fatal("the element was not found")
+	}
+	// A re-render patches the style while it is highlighted
+	style, _ := box.GetAttribute("style")
+	box.SetAttribute("style", strings.Replace(style, "color: red", "color: blue", 1))
+	ClearHighlight()
+	if style, _ := box.GetAttribute("style"); style != "color: blue" {
+		t.Fatalf("style after clearing = %q, want the re-rendered style alone", style)
+	}
+
+	// A re-render that dropped the highlight is left alone
+	HighlightComponent("box")
+	box.SetAttribute("style", "color: green")
+	ClearHighlight()
+	if style, _ := box.GetAttribute("style"); style != "color: green" {
+		t.Fatalf("style after clearing = %q, want it untouched", style)
+	}
+}
@@ -1,69 +1,259 @@
 package goFE
 
 import (
-	"syscall/js"
+	"sync"
 
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/vdom"
 	"github.com/google/uuid"
 )
 
-const renderNotifierBufferSize = 100
-
 type Document struct {
 	componentTree []Component
+	dom           dom.DOM
+	// rootID is the id of the element the document mounts into.
+	rootID string
+	// logger is the app's own logger, or nil for the package one.
+	logger *Logger
+
+	// vroot mirrors the children of the root element as a virtual tree, and
+	// vnodes indexes its elements by id so a component's previous render can
+	// be found and diffed against.
+	vroot  *vdom.Node
+	vnodes map[string]*vdom.Node
+
+	events *eventRegistry
+
+	// mounted holds the components whose OnMount has run.
+	mountedLock sync.Mutex
+	mounted     map[uuid.UUID]bool
+}
+
+// NewDocument creates a document for componentTree in the page's DOM, or in
+// an in-memory DOM when not built for the browser.
+func NewDocument(componentTree []Component) *Document {
+	return NewDocumentWithDOM(componentTree, defaultDOM())
+}
+
+// NewDocumentWithDOM creates a document for componentTree that mounts into
+// the #root element of the given DOM.
+func NewDocumentWithDOM(componentTree []Component, d dom.DOM) *Document {
+	return &Document{
+		componentTree: componentTree,
+		dom:           d,
+		rootID:        "root",
+		vroot:         &vdom.Node{Type: vdom.ElementNode, Tag: "div"},
+		vnodes:        make(map[string]*vdom.Node),
+		events:        newEventRegistry(),
+		mounted:       make(map[uuid.UUID]bool),
+	}
+}
 
-	// When any component's state changes, we should re-render the DOM
-	// from this element down
-	renderNotifier chan Component
-}
-
-// global document
-var document *Document
-var logger *Logger
-
-func Init(loggerInit *Logger) {
-	// Listen for any re-render events
-	if loggerInit != nil {
-		logger = loggerInit
-	} else {
-		logger = &Logger{Level: INFO}
-	}
-	go func() {
-		for {
-			select {
-			case component := <-document.renderNotifier:
-				//println("Re-rendering DOM from component with id: " + component.GetID().String())
-				rootElement := js.Global().Get("document").Call("getElementById", component.GetID().String())
-				rootElement.Set("outerHTML", component.Render())
-				initListeners([]Component{component})
+func (d *Document) Init() {
+	d.log(DEBUG, "Initializing document")
+	defer d.enter()()
+	rootElement := d.dom.GetElementByID(d.rootID)
+	if rootElement == nil {
+		d.log(ERROR, "No element to mount the document into", String("rootID", d.rootID))
+		return
+	}
+	buffer := renderComponents(d.componentTree)
+	rootElement.SetInnerHTML(buffer)
+	d.mount(rootElement, buffer)
+}
+
+// Hydrate is the counterpart of Init for pages whose root already holds the
+// output of RenderToString. It restores the serialized state into the
+// component tree and adopts the existing markup, patching only where the
+// client render differs, instead of replacing it.
+func (d *Document) Hydrate() {
+	d.log(DEBUG, "Hydrating document")
+	defer d.enter()()
+	rootElement := d.dom.GetElementByID(d.rootID)
+	if rootElement == nil {
+		d.log(ERROR, "No element to hydrate", String("rootID", d.rootID))
+		return
+	}
+	if script := d.dom.GetElementByID(StateScriptID); script == nil {
+		d.log(WARNING, "No server state found, hydrating with initial state")
+	} else if err := restoreStates(d.componentTree, script.TextContent()); err != nil {
+		d.log(WARNING, "Could not restore server state", Err(err))
+	}
+
+	buffer := renderComponents(d.componentTree)
+	existing := &vdom.Node{Type: vdom.ElementNode, Tag: "div"}
+	vdom.ParseInto(existing, rootElement.InnerHTML())
+	next := &vdom.Node{Type: vdom.ElementNode, Tag: "div"}
+	vdom.ParseInto(next, buffer)
+	// Component ids are generated afresh on the client, so match by position
+	if err := applyPatches(d.dom, rootElement, existing, vdom.DiffPositional(existing, next)); err != nil {
+		d.log(WARNING, "Server markup does not match, re-rendering", Err(err))
+		rootElement.SetInnerHTML(buffer)
+	}
+	d.mount(rootElement, buffer)
+}
+
+// mount records buffer as the current content of rootElement and sets up
+// event handling for the component tree.
+func (d *Document) mount(rootElement dom.Node, buffer string) {
+	d.events.attach(rootElement)
+	d.vroot.Children = nil
+	vdom.ParseInto(d.vroot, buffer)
+	d.indexVNodes(d.vroot)
+	d.mountPortals(d.componentTree)
+	d.initListeners(d.componentTree)
+	d.runLifecycle(d.componentTree)
+}
+
+// renderDirty re-renders the dirty components in tree order. A component is
+// skipped when one of its ancestors is also dirty, since rendering the
+// ancestor re-renders its whole subtree, except for the content of portals.
+// Components that belong to another document are left alone.
+func (d *Document) renderDirty(dirty map[uuid.UUID]bool) {
+	defer d.enter()()
+	var walk, walkPortals func(components []Component)
+	walk = func(components []Component) {
+		for _, component := range components {
+			if dirty[component.GetID()] {
+				d.rerender(component)
+				walkPortals(component.GetChildren())
+				continue
 			}
+			walk(component.GetChildren())
 		}
-	}()
+	}
+	walkPortals = func(components []Component) {
+		for _, component := range components {
+			if portal, ok := component.(*Portal); ok {
+				walk(portal.GetChildren())
+				continue
+			}
+			walkPortals(component.GetChildren())
+		}
+	}
+	walk(d.componentTree)
+}
+
+// rerender renders component again and patches only the DOM nodes that
+// changed since its last render, preserving focus, selection, scroll offsets
+// and input values everywhere else.
+func (d *Document) rerender(component Component) {
+	id := component.GetID().String()
+	old, ok := d.vnodes[id]
+	element := d.dom.GetElementByID(id)
+	if !ok || element == nil {
+		d.log(WARNING, "Component is not mounted, skipping render", ComponentID(component.GetID()))
+		return
+	}
+
+	var html string
+	if guard(component.GetID(), func() { html = renderComponent(component) }) {
+		return
+	}
+	next := componentRoot(vdom.Parse(html), id)
+	if next == nil {
+		// Without its root element the component could not be found to be
+		// patched again, so the previous render is kept
+		d.log(ERROR, "Render output has no element with the component's id, keeping the previous render", ComponentID(component.GetID()))
+		return
+	}
+
+	patches := vdom.Diff(old, next)
+	if err := applyPatches(d.dom, element, old, patches); err != nil {
+		d.log(WARNING, "Patching failed, replacing component", ComponentID(component.GetID()), Err(err))
+		element.SetOuterHTML(next.Render())
+	}
+	d.unindexVNodes(old)
+	old.ReplaceWith(next)
+	d.indexVNodes(next)
+	d.mountPortals([]Component{component})
+	d.initListeners([]Component{component})
+	d.runLifecycle([]Component{component})
 }
 
-func SetDocument(doc *Document) {
-	document = doc
+// runLifecycle is called once components have been rendered into the DOM.
+// Components rendered for the first time are mounted, and the others told
+// they were updated, children before their parents.
+func (d *Document) runLifecycle(components []Component) {
+	for _, component := range components {
+		d.runLifecycle(component.GetChildren())
+		d.mountedLock.Lock()
+		mounted := d.mounted[component.GetID()]
+		d.mounted[component.GetID()] = true
+		d.mountedLock.Unlock()
+		if !mounted {
+			if mounter, ok := component.(Mounter); ok {
+				guard(component.GetID(), mounter.OnMount)
+			}
+		} else if updater, ok := component.(Updater); ok {
+			guard(component.GetID(), updater.OnUpdate)
+		}
+	}
 }
 
-func GetDocument() *Document {
-	return document
+// unmount runs OnUnmount for component and its descendants, parents first,
+// skipping any that were never mounted.
+func (d *Document) unmount(component Component) {
+	d.mountedLock.Lock()
+	mounted := d.mounted[component.GetID()]
+	delete(d.mounted, component.GetID())
+	d.mountedLock.Unlock()
+	if unmounter, ok := component.(Unmounter); ok && mounted {
+		guard(component.GetID(), unmounter.OnUnmount)
+	}
+	for _, child := range component.GetChildren() {
+		d.unmount(child)
+	}
 }
 
-func NewDocument(componentTree []Component) *Document {
-	return &Document{
-		componentTree:  componentTree,
-		renderNotifier: make(chan Component, renderNotifierBufferSize),
+// componentRoot picks the element with the given id out of a parsed render,
+// ignoring the whitespace templates typically emit around it.
+func componentRoot(nodes []*vdom.Node, id string) *vdom.Node {
+	for _, node := range nodes {
+		if node.ID() == id {
+			return node
+		}
 	}
+	for _, node := range nodes {
+		if node.Type == vdom.ElementNode {
+			if found := node.FindByID(id); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
 }
 
-func (d *Document) Init() {
-	logger.Log(DEBUG, "Initializing document")
-	var buffer string
+func (d *Document) indexVNodes(root *vdom.Node) {
+	root.Walk(func(node *vdom.Node) {
+		if id := node.ID(); id != "" {
+			d.vnodes[id] = node
+		}
+	})
+}
+
+func (d *Document) unindexVNodes(root *vdom.Node) {
+	root.Walk(func(node *vdom.Node) {
+		if id := node.ID(); id != "" && d.vnodes[id] == node {
+			delete(d.vnodes, id)
+		}
+	})
+}
+
+// Unmount tears the document's component tree down: the components are
+// unmounted and their states killed, which runs their teardowns (such as
+// cancelling Resource loads and removing window listeners), the event
+// listeners are removed and the root element is emptied.
+func (d *Document) Unmount() {
 	for _, component := range d.componentTree {
-		buffer += component.Render()
+		killAllStates(component)
+	}
+	d.events.detach()
+	if root := d.dom.GetElementByID(d.rootID); root != nil {
+		root.SetInnerHTML("")
 	}
-	rootElement := js.Global().Get("document").Call("getElementById", "root")
-	rootElement.Set("innerHTML", buffer)
-	initListeners(d.componentTree)
+	d.vroot.Children = nil
+	d.vnodes = make(map[string]*vdom.Node)
 }
 
 func (d *Document) GetComponentTree() []Component {
@@ -74,27 +264,53 @@ func (d *Document) Append(component Component) {
 	d.componentTree = append(d.componentTree, component)
 }
 
-func (d *Document) AddEventListener(id uuid.UUID, event string, callback js.Func) {
-	logger.Log(DEBUG, "Adding event listener for component with id: "+id.String())
-	element := js.Global().Get("document").Call("getElementById", id.String())
-	if element.IsNull() || element.IsUndefined() {
-		logger.Log(WARNING, "Element not found for id: "+id.String())
-		return
+// DOM returns the DOM the document is mounted in, for access to history,
+// location and window events.
+func (d *Document) DOM() dom.DOM {
+	return d.dom
+}
+
+// On registers handler for event on the element with the given id. Handlers
+// are delegated: a single listener per event type lives on the root element
+// and dispatches to the handler of the target element or its closest
+// registered ancestor, which is the event's CurrentTarget. The element does
+// not need to exist yet.
+//
+// Handlers registered from InitEventListeners belong to that component and
+// are removed when its states are killed. Registering again for the same
+// element and event replaces the previous handler.
+func (d *Document) On(id uuid.UUID, event string, handler func(event dom.Event)) {
+	d.log(DEBUG, "Adding event listener", String("elementID", id.String()), String("event", event))
+	d.events.register(id.String(), event, delegatedHandler{handler: func(e dom.Event) {
+		defer d.enter()()
+		handler(e)
+	}})
+}
+
+// releaseListeners releases the event handlers owned by component and its
+// descendants.
+func (d *Document) releaseListeners(component Component) {
+	d.events.releaseOwner(component.GetID())
+	for _, child := range component.GetChildren() {
+		d.releaseListeners(child)
 	}
-	element.Call("addEventListener", event, callback)
 }
 
-func initListeners(components []Component) {
+func (d *Document) initListeners(components []Component) {
 	for _, component := range components {
-		component.InitEventListeners()
-		initListeners(component.GetChildren())
+		d.events.setOwner(component.GetID())
+		guard(component.GetID(), component.InitEventListeners)
+		d.events.setOwner(uuid.Nil)
+		d.initListeners(component.GetChildren())
 	}
 }
 
-func RenderChildren(component Component) string {
-	var buffer string
-	for _, child := range component.GetChildren() {
-		buffer += child.Render()
+// log logs message as "document", with the app's logger or else the
+// package one.
+func (d *Document) log(level int, message string, fields ...Field) {
+	if d.logger != nil {
+		d.logger.log("document", level, message, fields...)
+		return
 	}
-	return buffer
+	logger.log("document", level, message, fields...)
 }
@@ -0,0 +1,37 @@
+//go:build js
+
+package goFE
+
+import (
+	"syscall/js"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+func defaultDOM() dom.DOM {
+	return dom.Browser()
+}
+
+// AddEventListener registers a js.Func callback for event on the element with
+// the given id, with `this` bound to that element. It behaves like On, and
+// the callback is released when it is replaced or its owner is torn down.
+func (d *Document) AddEventListener(id uuid.UUID, event string, callback js.Func) {
+	d.log(DEBUG, "Adding event listener", String("elementID", id.String()), String("event", event))
+	d.events.register(id.String(), event, delegatedHandler{
+		handler: func(e dom.Event) {
+			defer d.enter()()
+			this := e.CurrentTarget().(interface{ JSValue() js.Value }).JSValue()
+			if delegated, ok := e.(*delegatedEvent); ok {
+				e = delegated.Event
+			}
+			callback.Call("call", this, e.(interface{ JSValue() js.Value }).JSValue())
+		},
+		release:  callback.Release,
+		resource: callback.Value,
+		sameResource: func(other interface{}) bool {
+			value, ok := other.(js.Value)
+			return ok && value.Equal(callback.Value)
+		},
+	})
+}
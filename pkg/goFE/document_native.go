@@ -0,0 +1,9 @@
+//go:build !js
+
+package goFE
+
+import "github.com/cstevenson98/goFE/pkg/goFE/dom"
+
+func defaultDOM() dom.DOM {
+	return dom.NewMemory()
+}
@@ -0,0 +1,162 @@
+package goFE
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+type testCounter struct {
+	id       uuid.UUID
+	buttonID uuid.UUID
+	state    *State[int]
+	setState func(*int)
+}
+
+func newTestCounter() *testCounter {
+	counter := &testCounter{id: uuid.New(), buttonID: uuid.New()}
+	counter.state, counter.setState = NewState[int](counter, new(int))
+	return counter
+}
+
+func (c *testCounter) Render() string {
+	return `<div id="` + c.id.String() + `"><input value="x"><span>` + strconv.Itoa(*c.state.Value) +
+		`</span><button id="` + c.buttonID.String() + `">+</button></div>`
+}
+func (c *testCounter) GetID() uuid.UUID         { return c.id }
+func (c *testCounter) GetChildren() []Component { return nil }
+func (c *testCounter) InitEventListeners() {
+	document.On(c.buttonID, "click", func(dom.Event) {
+		next := *c.state.Value + 1
+		c.setState(&next)
+	})
+}
+
+func TestDocument_PatchesInMemoryDOM(t *testing.T) {
+	memory := dom.NewMemory()
+	counter := newTestCounter()
+	SetDocument(NewDocumentWithDOM([]Component{counter}, memory))
+	defer SetDocument(nil)
+	document.Init()
+
+	root := memory.GetElementByID(counter.id.String())
+	input := root.ChildAt(0)
+	input.SetValue("typed")
+	for i := 0; i < 2; i++ {
+		memory.Dispatch(memory.GetElementByID(counter.buttonID.String()), "click")
+		sched.flush()
+	}
+
+	if got := root.ChildAt(1).TextContent(); got != "2" {
+		t.Fatalf("count = %q, want 2", got)
+	}
+	if !memory.GetElementByID(counter.id.String()).IsSameNode(root) || input.Value() != "typed" {
+		t.Fatal("re-render replaced nodes that did not change")
+	}
+
+	killAllStates(counter)
+	memory.Dispatch(memory.GetElementByID(counter.buttonID.String()), "click")
+	sched.flush()
+	if got := root.ChildAt(1).TextContent(); got != "2" {
+		t.Fatalf("count after teardown = %q, want 2", got)
+	}
+}
+
+type lifecycleProbe struct {
+	id       uuid.UUID
+	name     string
+	log      *[]string
+	children []Component
+	state    *State[int]
+	setState func(*int)
+}
+
+func newLifecycleProbe(name string, log *[]string, children ...Component) *lifecycleProbe {
+	probe := &lifecycleProbe{id: uuid.New(), name: name, log: log, children: children}
+	probe.state, probe.setState = NewState[int](probe, new(int))
+	return probe
+}
+
+func (p *lifecycleProbe) Render() string {
+	return `<div id="` + p.id.String() + `">` + strconv.Itoa(*p.state.Value) + RenderChildren(p) + `</div>`
+}
+func (p *lifecycleProbe) GetID() uuid.UUID         { return p.id }
+func (p *lifecycleProbe) GetChildren() []Component { return p.children }
+func (p *lifecycleProbe) InitEventListeners()      {}
+func (p *lifecycleProbe) OnMount()                 { *p.log = append(*p.log, "mount "+p.name) }
+func (p *lifecycleProbe) OnUpdate()                { *p.log = append(*p.log, "update "+p.name) }
+func (p *lifecycleProbe) OnUnmount()               { *p.log = append(*p.log, "unmount "+p.name) }
+
+func TestDocument_Lifecycle(t *testing.T) {
+	var log []string
+	slot := NewSwappableComponent(newLifecycleProbe("first", &log))
+	parent := newLifecycleProbe("parent", &log, slot)
+	SetDocument(NewDocumentWithDOM([]Component{parent}, dom.NewMemory()))
+	defer SetDocument(nil)
+	document.Init()
+
+	one := 1
+	parent.setState(&one)
+	Flush()
+	slot.Swap(newLifecycleProbe("second", &log))
+	parent.setState(new(int))
+	Flush()
+	killAllStates(parent)
+
+	want := []string{
+		"mount first", "mount parent",
+		"update first", "update parent",
+		"unmount first", "mount second", "update parent",
+		"unmount parent", "unmount second",
+	}
+	if strings.Join(log, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("lifecycle calls:\n got %v\nwant %v", log, want)
+	}
+}
+
+// rootless renders without its id while broken is set.
+type rootless struct {
+	id       uuid.UUID
+	broken   bool
+	state    *State[int]
+	setState func(*int)
+}
+
+func (r *rootless) Render() string {
+	if r.broken {
+		return `<p>` + strconv.Itoa(*r.state.Value) + `</p>`
+	}
+	return `<p id="` + r.id.String() + `">` + strconv.Itoa(*r.state.Value) + `</p>`
+}
+func (r *rootless) GetID() uuid.UUID         { return r.id }
+func (r *rootless) GetChildren() []Component { return nil }
+func (r *rootless) InitEventListeners()      {}
+
+func TestDocument_RefusesRenderWithoutRoot(t *testing.T) {
+	memory := dom.NewMemory()
+	component := &rootless{id: uuid.New()}
+	component.state, component.setState = NewState[int](component, new(int))
+	SetDocument(NewDocumentWithDOM([]Component{component}, memory))
+	defer SetDocument(nil)
+	document.Init()
+	defer killAllStates(component)
+
+	component.broken = true
+	one := 1
+	component.setState(&one)
+	Flush()
+	if got := memory.GetElementByID("root").InnerHTML(); got != `<p id="`+component.id.String()+`">0</p>` {
+		t.Fatalf("a render without the component's root was applied: %s", got)
+	}
+
+	component.broken = false
+	two := 2
+	component.setState(&two)
+	Flush()
+	if got := memory.GetElementByID("root").InnerHTML(); got != `<p id="`+component.id.String()+`">2</p>` {
+		t.Fatalf("the next good render was not patched in: %s", got)
+	}
+}
@@ -0,0 +1,336 @@
+//go:build js
+
+package dom
+
+import "syscall/js"
+
+type browser struct {
+	window   js.Value
+	document js.Value
+}
+
+// Browser returns the DOM of the page the program is running in.
+func Browser() DOM {
+	window := js.Global()
+	return &browser{window: window, document: window.Get("document")}
+}
+
+func (b *browser) GetElementByID(id string) Node {
+	return wrapNode(b.document.Call("getElementById", id))
+}
+
+func (b *browser) CreateElement(tag string) Node {
+	return wrapNode(b.document.Call("createElement", tag))
+}
+
+func (b *browser) CreateElementNS(namespace, tag string) Node {
+	return wrapNode(b.document.Call("createElementNS", namespace, tag))
+}
+
+func (b *browser) CreateTextNode(text string) Node {
+	return wrapNode(b.document.Call("createTextNode", text))
+}
+
+func (b *browser) CreateComment(text string) Node {
+	return wrapNode(b.document.Call("createComment", text))
+}
+
+func (b *browser) Location() Location {
+	location := b.window.Get("location")
+	return Location{
+		Href:     location.Get("href").String(),
+		Pathname: location.Get("pathname").String(),
+		Search:   location.Get("search").String(),
+		Hash:     location.Get("hash").String(),
+	}
+}
+
+func (b *browser) PushState(url string) {
+	b.window.Get("history").Call("pushState", nil, "", url)
+}
+
+func (b *browser) ReplaceState(url string) {
+	b.window.Get("history").Call("replaceState", nil, "", url)
+}
+
+func (b *browser) Back() {
+	b.window.Get("history").Call("back")
+}
+
+func (b *browser) Forward() {
+	b.window.Get("history").Call("forward")
+}
+
+func (b *browser) AddWindowListener(event string, handler func(Event)) func() {
+	return addListener(b.window, event, false, handler)
+}
+
+func (b *browser) RequestAnimationFrame(callback func()) {
+	var frame js.Func
+	frame = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		frame.Release()
+		callback()
+		return nil
+	})
+	b.window.Call("requestAnimationFrame", frame)
+}
+
+func (b *browser) LocalStorage() Storage {
+	return browserStorage{value: b.window.Get("localStorage")}
+}
+
+func (b *browser) SessionStorage() Storage {
+	return browserStorage{value: b.window.Get("sessionStorage")}
+}
+
+// addListener adds handler to target and returns a function that removes it
+// and releases the underlying js.Func.
+func addListener(target js.Value, event string, capture bool, handler func(Event)) func() {
+	listener := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		handler(browserEvent{value: args[0]})
+		return nil
+	})
+	target.Call("addEventListener", event, listener, capture)
+	return func() {
+		target.Call("removeEventListener", event, listener, capture)
+		listener.Release()
+	}
+}
+
+// browserNode wraps a live DOM node.
+type browserNode struct {
+	value js.Value
+}
+
+func wrapNode(value js.Value) Node {
+	if value.IsNull() || value.IsUndefined() {
+		return nil
+	}
+	return browserNode{value: value}
+}
+
+// JSValue returns the underlying DOM node.
+func (n browserNode) JSValue() js.Value {
+	return n.value
+}
+
+func (n browserNode) NodeType() int {
+	return n.value.Get("nodeType").Int()
+}
+
+func (n browserNode) NodeName() string {
+	return n.value.Get("nodeName").String()
+}
+
+func (n browserNode) ID() string {
+	id := n.value.Get("id")
+	if id.Type() != js.TypeString {
+		return ""
+	}
+	return id.String()
+}
+
+func (n browserNode) IsSameNode(other Node) bool {
+	o, ok := other.(browserNode)
+	return ok && n.value.Equal(o.value)
+}
+
+func (n browserNode) ParentNode() Node {
+	return wrapNode(n.value.Get("parentNode"))
+}
+
+func (n browserNode) ChildAt(i int) Node {
+	children := n.value.Get("childNodes")
+	if i < 0 || i >= children.Get("length").Int() {
+		return nil
+	}
+	return wrapNode(children.Index(i))
+}
+
+func (n browserNode) ChildCount() int {
+	return n.value.Get("childNodes").Get("length").Int()
+}
+
+func (n browserNode) AppendChild(child Node) {
+	n.value.Call("appendChild", jsValue(child))
+}
+
+func (n browserNode) InsertBefore(child, reference Node) {
+	n.value.Call("insertBefore", jsValue(child), jsValue(reference))
+}
+
+func (n browserNode) RemoveChild(child Node) {
+	n.value.Call("removeChild", jsValue(child))
+}
+
+func (n browserNode) ReplaceChild(newChild, oldChild Node) {
+	n.value.Call("replaceChild", jsValue(newChild), jsValue(oldChild))
+}
+
+func (n browserNode) GetAttribute(key string) (string, bool) {
+	value := n.value.Call("getAttribute", key)
+	if value.IsNull() {
+		return "", false
+	}
+	return value.String(), true
+}
+
+func (n browserNode) SetAttribute(key, value string) {
+	n.value.Call("setAttribute", key, value)
+}
+
+func (n browserNode) RemoveAttribute(key string) {
+	n.value.Call("removeAttribute", key)
+}
+
+func (n browserNode) Value() string {
+	value := n.value.Get("value")
+	if value.Type() != js.TypeString {
+		return ""
+	}
+	return value.String()
+}
+
+func (n browserNode) SetValue(value string) {
+	n.value.Set("value", value)
+}
+
+func (n browserNode) Checked() bool {
+	return n.value.Get("checked").Truthy()
+}
+
+func (n browserNode) SetChecked(checked bool) {
+	n.value.Set("checked", checked)
+}
+
+func (n browserNode) SetSelected(selected bool) {
+	n.value.Set("selected", selected)
+}
+
+func (n browserNode) NodeValue() string {
+	value := n.value.Get("nodeValue")
+	if value.IsNull() {
+		return ""
+	}
+	return value.String()
+}
+
+func (n browserNode) SetNodeValue(value string) {
+	n.value.Set("nodeValue", value)
+}
+
+func (n browserNode) TextContent() string {
+	return n.value.Get("textContent").String()
+}
+
+func (n browserNode) InnerHTML() string {
+	return n.value.Get("innerHTML").String()
+}
+
+func (n browserNode) SetInnerHTML(html string) {
+	n.value.Set("innerHTML", html)
+}
+
+func (n browserNode) SetOuterHTML(html string) {
+	n.value.Set("outerHTML", html)
+}
+
+func (n browserNode) AddEventListener(event string, capture bool, handler func(Event)) func() {
+	return addListener(n.value, event, capture, handler)
+}
+
+func jsValue(node Node) js.Value {
+	if node == nil {
+		return js.Null()
+	}
+	return node.(browserNode).value
+}
+
+// browserEvent wraps a DOM event.
+type browserEvent struct {
+	value js.Value
+}
+
+// JSValue returns the underlying DOM event.
+func (e browserEvent) JSValue() js.Value {
+	return e.value
+}
+
+func (e browserEvent) Type() string {
+	return e.value.Get("type").String()
+}
+
+func (e browserEvent) Target() Node {
+	return wrapNode(e.value.Get("target"))
+}
+
+func (e browserEvent) CurrentTarget() Node {
+	return wrapNode(e.value.Get("currentTarget"))
+}
+
+func (e browserEvent) PreventDefault() {
+	e.value.Call("preventDefault")
+}
+
+func (e browserEvent) DefaultPrevented() bool {
+	return e.value.Get("defaultPrevented").Truthy()
+}
+
+func (e browserEvent) StopPropagation() {
+	e.value.Call("stopPropagation")
+}
+
+func (e browserEvent) PropagationStopped() bool {
+	return e.value.Get("cancelBubble").Truthy()
+}
+
+func (e browserEvent) Key() string {
+	key := e.value.Get("key")
+	if key.Type() != js.TypeString {
+		return ""
+	}
+	return key.String()
+}
+
+func (e browserEvent) Modifiers() Modifiers {
+	var modifiers Modifiers
+	for key, modifier := range map[string]Modifiers{"shiftKey": Shift, "ctrlKey": Ctrl, "altKey": Alt, "metaKey": Meta} {
+		if e.value.Get(key).Truthy() {
+			modifiers |= modifier
+		}
+	}
+	return modifiers
+}
+
+func (e browserEvent) Value() string {
+	target := e.value.Get("target")
+	if target.IsNull() || target.IsUndefined() {
+		return ""
+	}
+	value := target.Get("value")
+	if value.Type() != js.TypeString {
+		return ""
+	}
+	return value.String()
+}
+
+// browserStorage wraps a Storage object of the window.
+type browserStorage struct {
+	value js.Value
+}
+
+func (s browserStorage) GetItem(key string) (string, bool) {
+	item := s.value.Call("getItem", key)
+	if item.Type() != js.TypeString {
+		return "", false
+	}
+	return item.String(), true
+}
+
+func (s browserStorage) SetItem(key, value string) {
+	s.value.Call("setItem", key, value)
+}
+
+func (s browserStorage) RemoveItem(key string) {
+	s.value.Call("removeItem", key)
+}
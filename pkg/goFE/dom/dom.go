@@ -0,0 +1,157 @@
+// Package dom is the small slice of the browser DOM that goFE needs. The
+// Browser implementation (js builds only) talks to the real document through
+// syscall/js; Memory is an in-memory document that lets the framework and its
+// components run under plain `go test`.
+package dom
+
+// Node types, as reported by Node.NodeType.
+const (
+	ElementNode = 1
+	TextNode    = 3
+	CommentNode = 8
+)
+
+// SVGNamespace is the namespace of elements inside an <svg> element.
+const SVGNamespace = "http://www.w3.org/2000/svg"
+
+// DOM is a document together with the window APIs goFE uses.
+type DOM interface {
+	// GetElementByID returns the element with the given id, or nil.
+	GetElementByID(id string) Node
+	CreateElement(tag string) Node
+	CreateElementNS(namespace, tag string) Node
+	CreateTextNode(text string) Node
+	CreateComment(text string) Node
+
+	// Location returns the current URL.
+	Location() Location
+	// PushState adds url to the session history without loading it.
+	PushState(url string)
+	// ReplaceState replaces the current history entry with url.
+	ReplaceState(url string)
+	// Back and Forward move through the session history, firing popstate.
+	Back()
+	Forward()
+
+	// AddWindowListener listens for event on the window (e.g. popstate,
+	// keydown) and returns a function that removes the listener.
+	AddWindowListener(event string, handler func(Event)) (remove func())
+	// RequestAnimationFrame calls callback before the next repaint.
+	RequestAnimationFrame(callback func())
+
+	// LocalStorage and SessionStorage return the window's storage areas.
+	// Changes made to local storage by other tabs fire storage on the
+	// window, with the changed key as the event's Key.
+	LocalStorage() Storage
+	SessionStorage() Storage
+}
+
+// Storage is a string key/value store such as window.localStorage.
+type Storage interface {
+	// GetItem returns the value stored under key, and whether there is one.
+	GetItem(key string) (string, bool)
+	SetItem(key, value string)
+	RemoveItem(key string)
+}
+
+// Node is an element, text or comment node.
+type Node interface {
+	NodeType() int
+	// NodeName is the upper-case tag name for HTML elements, the tag as
+	// written for SVG elements, and "#text" or "#comment" otherwise.
+	NodeName() string
+	// ID returns the id attribute of an element, or "".
+	ID() string
+	// IsSameNode reports whether other refers to the same node as this one.
+	IsSameNode(other Node) bool
+
+	// ParentNode returns the parent, or nil for a detached node.
+	ParentNode() Node
+	// ChildAt returns child i, or nil if there are not that many children.
+	ChildAt(i int) Node
+	ChildCount() int
+	AppendChild(child Node)
+	// InsertBefore inserts child before reference, or at the end when
+	// reference is nil. A child already in the tree is moved.
+	InsertBefore(child, reference Node)
+	RemoveChild(child Node)
+	ReplaceChild(newChild, oldChild Node)
+
+	GetAttribute(key string) (string, bool)
+	SetAttribute(key, value string)
+	RemoveAttribute(key string)
+
+	// Value, Checked and Selected are the live properties of form controls,
+	// which stop following their attributes once the user interacts.
+	Value() string
+	SetValue(value string)
+	Checked() bool
+	SetChecked(checked bool)
+	SetSelected(selected bool)
+
+	// NodeValue is the content of a text or comment node.
+	NodeValue() string
+	SetNodeValue(value string)
+	TextContent() string
+	InnerHTML() string
+	SetInnerHTML(html string)
+	SetOuterHTML(html string)
+
+	// AddEventListener listens for event on this node, in the capture phase
+	// if capture is set, and returns a function that removes the listener.
+	AddEventListener(event string, capture bool, handler func(Event)) (remove func())
+}
+
+// Event is a DOM event passed to a listener.
+type Event interface {
+	Type() string
+	// Target is the node the event was dispatched to.
+	Target() Node
+	// CurrentTarget is the node whose listener is being called.
+	CurrentTarget() Node
+	PreventDefault()
+	DefaultPrevented() bool
+	StopPropagation()
+	PropagationStopped() bool
+	// Key is the key of a keyboard event or the changed key of a storage
+	// event, or "".
+	Key() string
+	// Modifiers are the modifier keys held during a keyboard or mouse event.
+	Modifiers() Modifiers
+	// Value is the value of the target if it is a form control, or "".
+	Value() string
+}
+
+// Modifiers is a set of modifier keys.
+type Modifiers int
+
+const (
+	Shift Modifiers = 1 << iota
+	Ctrl
+	Alt
+	Meta
+)
+
+// Has reports whether every key in keys is held.
+func (m Modifiers) Has(keys Modifiers) bool {
+	return m&keys == keys
+}
+
+// Location is the URL of the document.
+type Location struct {
+	Href     string
+	Pathname string
+	Search   string
+	Hash     string
+}
+
+// Bubbles reports whether events of the given type bubble up from their
+// target. The few that do not can still be caught in the capture phase.
+func Bubbles(event string) bool {
+	switch event {
+	case "focus", "blur", "mouseenter", "mouseleave", "pointerenter",
+		"pointerleave", "load", "error", "scroll":
+		return false
+	}
+	return true
+}
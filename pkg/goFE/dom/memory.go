@@ -0,0 +1,731 @@
+package dom
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/vdom"
+)
+
+// Memory is an in-memory DOM for running components outside the browser,
+// typically in tests. HTML is parsed and serialised with the vdom package,
+// events are dispatched with capture and bubbling like a browser would, and
+// history is kept as a list of URLs.
+//
+// Memory is not safe for concurrent use.
+type Memory struct {
+	documentElement *memoryNode
+	body            *memoryNode
+	// window holds listeners added with AddWindowListener. It sits above the
+	// document element in the propagation path of every event.
+	window  *memoryNode
+	history []*url.URL
+	current int
+	local   memoryStorage
+	session memoryStorage
+}
+
+// NewMemory returns an empty document whose body holds a single
+// <div id="root">, at the URL http://localhost/.
+func NewMemory() *Memory {
+	m := &Memory{local: memoryStorage{}, session: memoryStorage{}}
+	m.window = &memoryNode{doc: m}
+	m.documentElement = m.newElement("html", false)
+	m.body = m.newElement("body", false)
+	m.documentElement.AppendChild(m.body)
+	root := m.newElement("div", false)
+	root.SetAttribute("id", "root")
+	m.body.AppendChild(root)
+	start, _ := url.Parse("http://localhost/")
+	m.history = []*url.URL{start}
+	return m
+}
+
+// Body returns the <body> element.
+func (m *Memory) Body() Node {
+	return m.body
+}
+
+func (m *Memory) GetElementByID(id string) Node {
+	if found := m.documentElement.findByID(id); found != nil {
+		return found
+	}
+	return nil
+}
+
+func (m *Memory) CreateElement(tag string) Node {
+	return m.newElement(strings.ToLower(tag), false)
+}
+
+func (m *Memory) CreateElementNS(namespace, tag string) Node {
+	if namespace == "" || namespace == "http://www.w3.org/1999/xhtml" {
+		return m.CreateElement(tag)
+	}
+	return m.newElement(tag, true)
+}
+
+func (m *Memory) CreateTextNode(text string) Node {
+	return &memoryNode{doc: m, nodeType: TextNode, text: text}
+}
+
+func (m *Memory) CreateComment(text string) Node {
+	return &memoryNode{doc: m, nodeType: CommentNode, text: text}
+}
+
+func (m *Memory) newElement(tag string, foreign bool) *memoryNode {
+	return &memoryNode{doc: m, nodeType: ElementNode, tag: tag, foreign: foreign}
+}
+
+func (m *Memory) Location() Location {
+	u := m.history[m.current]
+	hash := ""
+	if u.Fragment != "" {
+		hash = "#" + u.Fragment
+	}
+	search := ""
+	if u.RawQuery != "" {
+		search = "?" + u.RawQuery
+	}
+	return Location{Href: u.String(), Pathname: u.EscapedPath(), Search: search, Hash: hash}
+}
+
+func (m *Memory) resolve(ref string) *url.URL {
+	u, err := m.history[m.current].Parse(ref)
+	if err != nil {
+		return m.history[m.current]
+	}
+	return u
+}
+
+func (m *Memory) PushState(ref string) {
+	m.history = append(m.history[:m.current+1], m.resolve(ref))
+	m.current++
+}
+
+func (m *Memory) ReplaceState(ref string) {
+	m.history[m.current] = m.resolve(ref)
+}
+
+func (m *Memory) Back() {
+	if m.current > 0 {
+		m.current--
+		m.dispatchWindow("popstate")
+	}
+}
+
+func (m *Memory) Forward() {
+	if m.current < len(m.history)-1 {
+		m.current++
+		m.dispatchWindow("popstate")
+	}
+}
+
+func (m *Memory) AddWindowListener(event string, handler func(Event)) func() {
+	return m.window.AddEventListener(event, false, handler)
+}
+
+// RequestAnimationFrame calls callback straight away, as there are no frames
+// to wait for.
+func (m *Memory) RequestAnimationFrame(callback func()) {
+	callback()
+}
+
+func (m *Memory) LocalStorage() Storage {
+	return m.local
+}
+
+func (m *Memory) SessionStorage() Storage {
+	return m.session
+}
+
+// SetItemFromAnotherTab changes local storage the way another tab of the
+// same site would, firing storage on the window.
+func (m *Memory) SetItemFromAnotherTab(key, value string) {
+	m.local.SetItem(key, value)
+	m.dispatch(&memoryEvent{eventType: "storage", key: key, target: m.window})
+}
+
+// Dispatch fires an event of the given type at target and returns it once
+// every listener has run. Clicks perform the browser's default actions:
+// checkboxes and radio buttons are toggled, firing input and change once the
+// click's listeners have run, and submit buttons fire submit on their form,
+// unless a listener prevents it.
+func (m *Memory) Dispatch(target Node, eventType string) Event {
+	return m.DispatchKey(target, eventType, "")
+}
+
+// DispatchKey is like Dispatch for keyboard events carrying key, pressed
+// with the given modifier keys held.
+func (m *Memory) DispatchKey(target Node, eventType, key string, modifiers ...Modifiers) Event {
+	node := target.(*memoryNode)
+	event := &memoryEvent{eventType: eventType, key: key, target: node}
+	for _, modifier := range modifiers {
+		event.modifiers |= modifier
+	}
+
+	// Checkboxes and radio buttons toggle before the listeners run and
+	// revert if one of them prevents the default, as in browsers
+	toggled, wasChecked := false, node.Checked()
+	if eventType == "click" && node.nodeType == ElementNode && node.tag == "input" {
+		inputType, _ := node.GetAttribute("type")
+		switch strings.ToLower(inputType) {
+		case "checkbox":
+			node.SetChecked(!wasChecked)
+			toggled = true
+		case "radio":
+			node.SetChecked(true)
+			toggled = true
+		}
+	}
+
+	m.dispatch(event)
+	if event.prevented {
+		if toggled {
+			node.checked = &wasChecked
+		}
+		return event
+	}
+	if toggled && node.Checked() != wasChecked {
+		m.Dispatch(node, "input")
+		m.Dispatch(node, "change")
+	}
+	if eventType == "click" && node.isSubmitButton() {
+		if form := node.closest("form"); form != nil {
+			m.Dispatch(form, "submit")
+		}
+	}
+	return event
+}
+
+func (m *Memory) dispatchWindow(eventType string) {
+	m.dispatch(&memoryEvent{eventType: eventType, target: m.window})
+}
+
+// dispatch runs the capture phase from the window down to the target, the
+// target's own listeners, then the bubble phase back up.
+func (m *Memory) dispatch(event *memoryEvent) {
+	var path []*memoryNode
+	for node := event.target; node != nil; node = node.parent {
+		path = append(path, node)
+	}
+	if event.target != m.window && path[len(path)-1] == m.documentElement {
+		path = append(path, m.window)
+	}
+
+	for i := len(path) - 1; i > 0 && !event.stopped; i-- {
+		path[i].fire(event, true, false)
+	}
+	if !event.stopped {
+		event.target.fire(event, true, true)
+	}
+	if !Bubbles(event.eventType) {
+		return
+	}
+	for i := 1; i < len(path) && !event.stopped; i++ {
+		path[i].fire(event, false, false)
+	}
+}
+
+// memoryStorage is a storage area of a Memory document.
+type memoryStorage map[string]string
+
+func (s memoryStorage) GetItem(key string) (string, bool) {
+	value, ok := s[key]
+	return value, ok
+}
+
+func (s memoryStorage) SetItem(key, value string) {
+	s[key] = value
+}
+
+func (s memoryStorage) RemoveItem(key string) {
+	delete(s, key)
+}
+
+type memoryListener struct {
+	event   string
+	capture bool
+	handler func(Event)
+	removed bool
+}
+
+// memoryNode is a node of a Memory document. The window is represented by a
+// node with no type that is never part of the tree.
+type memoryNode struct {
+	doc      *Memory
+	nodeType int
+	// tag is lower-case for HTML elements and as written for foreign ones.
+	tag      string
+	foreign  bool
+	attrs    []vdom.Attribute
+	text     string
+	parent   *memoryNode
+	children []*memoryNode
+
+	// value, checked and selected shadow the attributes once set.
+	value    *string
+	checked  *bool
+	selected *bool
+
+	listeners []*memoryListener
+}
+
+func (n *memoryNode) NodeType() int {
+	return n.nodeType
+}
+
+func (n *memoryNode) NodeName() string {
+	switch n.nodeType {
+	case TextNode:
+		return "#text"
+	case CommentNode:
+		return "#comment"
+	}
+	if n.foreign {
+		return n.tag
+	}
+	return strings.ToUpper(n.tag)
+}
+
+func (n *memoryNode) ID() string {
+	id, _ := n.GetAttribute("id")
+	return id
+}
+
+func (n *memoryNode) IsSameNode(other Node) bool {
+	o, ok := other.(*memoryNode)
+	return ok && o == n
+}
+
+func (n *memoryNode) ParentNode() Node {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *memoryNode) ChildAt(i int) Node {
+	if i < 0 || i >= len(n.children) {
+		return nil
+	}
+	return n.children[i]
+}
+
+func (n *memoryNode) ChildCount() int {
+	return len(n.children)
+}
+
+func (n *memoryNode) AppendChild(child Node) {
+	n.InsertBefore(child, nil)
+}
+
+func (n *memoryNode) InsertBefore(child, reference Node) {
+	c := child.(*memoryNode)
+	if reference != nil && reference.(*memoryNode) == c {
+		return
+	}
+	c.detach()
+	index := len(n.children)
+	if reference != nil {
+		index = n.indexOf(reference.(*memoryNode))
+		if index < 0 {
+			panic("dom: reference node is not a child of this node")
+		}
+	}
+	n.children = append(n.children, nil)
+	copy(n.children[index+1:], n.children[index:])
+	n.children[index] = c
+	c.parent = n
+}
+
+func (n *memoryNode) RemoveChild(child Node) {
+	c := child.(*memoryNode)
+	if c.parent != n {
+		panic("dom: node to remove is not a child of this node")
+	}
+	c.detach()
+}
+
+func (n *memoryNode) ReplaceChild(newChild, oldChild Node) {
+	n.InsertBefore(newChild, oldChild)
+	n.RemoveChild(oldChild)
+}
+
+func (n *memoryNode) indexOf(child *memoryNode) int {
+	for i, c := range n.children {
+		if c == child {
+			return i
+		}
+	}
+	return -1
+}
+
+func (n *memoryNode) detach() {
+	if n.parent == nil {
+		return
+	}
+	if i := n.parent.indexOf(n); i >= 0 {
+		n.parent.children = append(n.parent.children[:i], n.parent.children[i+1:]...)
+	}
+	n.parent = nil
+}
+
+func (n *memoryNode) GetAttribute(key string) (string, bool) {
+	if n.nodeType != ElementNode {
+		return "", false
+	}
+	for _, attr := range n.attrs {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+func (n *memoryNode) SetAttribute(key, value string) {
+	for i, attr := range n.attrs {
+		if attr.Key == key {
+			n.attrs[i].Val = value
+			return
+		}
+	}
+	n.attrs = append(n.attrs, vdom.Attribute{Key: key, Val: value})
+}
+
+func (n *memoryNode) RemoveAttribute(key string) {
+	for i, attr := range n.attrs {
+		if attr.Key == key {
+			n.attrs = append(n.attrs[:i], n.attrs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (n *memoryNode) Value() string {
+	if n.value != nil {
+		return *n.value
+	}
+	switch n.tag {
+	case "textarea":
+		return n.TextContent()
+	case "select":
+		var first *memoryNode
+		var selected *memoryNode
+		n.walk(func(node *memoryNode) {
+			if node.tag != "option" {
+				return
+			}
+			if first == nil {
+				first = node
+			}
+			if selected == nil && node.isSelected() {
+				selected = node
+			}
+		})
+		if selected == nil {
+			selected = first
+		}
+		if selected == nil {
+			return ""
+		}
+		return selected.Value()
+	case "option":
+		if value, ok := n.GetAttribute("value"); ok {
+			return value
+		}
+		return n.TextContent()
+	}
+	value, _ := n.GetAttribute("value")
+	return value
+}
+
+func (n *memoryNode) SetValue(value string) {
+	if n.tag == "select" {
+		n.walk(func(node *memoryNode) {
+			if node.tag == "option" {
+				node.SetSelected(node.Value() == value)
+			}
+		})
+		return
+	}
+	n.value = &value
+}
+
+func (n *memoryNode) Checked() bool {
+	if n.checked != nil {
+		return *n.checked
+	}
+	_, ok := n.GetAttribute("checked")
+	return ok
+}
+
+func (n *memoryNode) SetChecked(checked bool) {
+	n.checked = &checked
+	inputType, _ := n.GetAttribute("type")
+	name, _ := n.GetAttribute("name")
+	if !checked || strings.ToLower(inputType) != "radio" || name == "" {
+		return
+	}
+	// Checking a radio button unchecks the others in its group
+	scope := n.closest("form")
+	if scope == nil {
+		scope = n.root()
+	}
+	scope.walk(func(node *memoryNode) {
+		if node == n || node.tag != "input" {
+			return
+		}
+		otherType, _ := node.GetAttribute("type")
+		otherName, _ := node.GetAttribute("name")
+		if strings.ToLower(otherType) == "radio" && otherName == name {
+			unchecked := false
+			node.checked = &unchecked
+		}
+	})
+}
+
+func (n *memoryNode) SetSelected(selected bool) {
+	n.selected = &selected
+}
+
+func (n *memoryNode) isSelected() bool {
+	if n.selected != nil {
+		return *n.selected
+	}
+	_, ok := n.GetAttribute("selected")
+	return ok
+}
+
+func (n *memoryNode) NodeValue() string {
+	return n.text
+}
+
+func (n *memoryNode) SetNodeValue(value string) {
+	if n.nodeType != ElementNode {
+		n.text = value
+	}
+}
+
+func (n *memoryNode) TextContent() string {
+	if n.nodeType != ElementNode {
+		return n.text
+	}
+	var sb strings.Builder
+	n.walk(func(node *memoryNode) {
+		if node.nodeType == TextNode {
+			sb.WriteString(node.text)
+		}
+	})
+	return sb.String()
+}
+
+func (n *memoryNode) InnerHTML() string {
+	return n.toVNode().InnerHTML()
+}
+
+func (n *memoryNode) SetInnerHTML(html string) {
+	for len(n.children) > 0 {
+		n.children[0].detach()
+	}
+	for _, child := range n.parse(html) {
+		n.AppendChild(child)
+	}
+}
+
+func (n *memoryNode) SetOuterHTML(html string) {
+	parent := n.parent
+	if parent == nil {
+		return
+	}
+	for _, node := range parent.parse(html) {
+		parent.InsertBefore(node, n)
+	}
+	n.detach()
+}
+
+// parse parses html as content of n.
+func (n *memoryNode) parse(html string) []*memoryNode {
+	holder := &vdom.Node{Type: vdom.ElementNode, Tag: n.tag}
+	vdom.ParseInto(holder, html)
+	nodes := make([]*memoryNode, 0, len(holder.Children))
+	for _, child := range holder.Children {
+		nodes = append(nodes, n.doc.fromVNode(child, n.foreign))
+	}
+	return nodes
+}
+
+func (m *Memory) fromVNode(v *vdom.Node, foreign bool) *memoryNode {
+	switch v.Type {
+	case vdom.TextNode:
+		return &memoryNode{doc: m, nodeType: TextNode, text: v.Text}
+	case vdom.CommentNode:
+		return &memoryNode{doc: m, nodeType: CommentNode, text: v.Text}
+	}
+	foreign = foreign || v.IsForeign()
+	tag := v.Tag
+	if !foreign {
+		tag = strings.ToLower(tag)
+	}
+	node := m.newElement(tag, foreign)
+	node.attrs = append(node.attrs, v.Attrs...)
+	for _, child := range v.Children {
+		node.AppendChild(m.fromVNode(child, foreign))
+	}
+	return node
+}
+
+func (n *memoryNode) toVNode() *vdom.Node {
+	switch n.nodeType {
+	case TextNode:
+		return &vdom.Node{Type: vdom.TextNode, Text: n.text}
+	case CommentNode:
+		return &vdom.Node{Type: vdom.CommentNode, Text: n.text}
+	}
+	v := &vdom.Node{Type: vdom.ElementNode, Tag: n.tag}
+	v.Attrs = append(v.Attrs, n.attrs...)
+	for _, child := range n.children {
+		v.AppendChild(child.toVNode())
+	}
+	return v
+}
+
+func (n *memoryNode) AddEventListener(event string, capture bool, handler func(Event)) func() {
+	listener := &memoryListener{event: event, capture: capture, handler: handler}
+	n.listeners = append(n.listeners, listener)
+	return func() {
+		listener.removed = true
+		for i, l := range n.listeners {
+			if l == listener {
+				n.listeners = append(n.listeners[:i], n.listeners[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// fire runs the listeners of n for event. At the target both capture and
+// bubble listeners run, in the order they were added.
+func (n *memoryNode) fire(event *memoryEvent, capture, atTarget bool) {
+	if n == n.doc.window {
+		event.current = nil
+	} else {
+		event.current = n
+	}
+	listeners := append([]*memoryListener{}, n.listeners...)
+	for _, listener := range listeners {
+		if listener.removed || listener.event != event.eventType || (!atTarget && listener.capture != capture) {
+			continue
+		}
+		listener.handler(event)
+	}
+}
+
+func (n *memoryNode) walk(fn func(*memoryNode)) {
+	fn(n)
+	for _, child := range n.children {
+		child.walk(fn)
+	}
+}
+
+func (n *memoryNode) findByID(id string) *memoryNode {
+	if n.nodeType == ElementNode && n.ID() == id {
+		return n
+	}
+	for _, child := range n.children {
+		if found := child.findByID(id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (n *memoryNode) closest(tag string) *memoryNode {
+	for node := n; node != nil; node = node.parent {
+		if node.nodeType == ElementNode && node.tag == tag {
+			return node
+		}
+	}
+	return nil
+}
+
+func (n *memoryNode) root() *memoryNode {
+	node := n
+	for node.parent != nil {
+		node = node.parent
+	}
+	return node
+}
+
+func (n *memoryNode) isSubmitButton() bool {
+	if n.nodeType != ElementNode {
+		return false
+	}
+	buttonType, ok := n.GetAttribute("type")
+	buttonType = strings.ToLower(buttonType)
+	switch n.tag {
+	case "button":
+		return !ok || buttonType == "submit"
+	case "input":
+		return buttonType == "submit"
+	}
+	return false
+}
+
+type memoryEvent struct {
+	eventType string
+	key       string
+	modifiers Modifiers
+	target    *memoryNode
+	current   *memoryNode
+	prevented bool
+	stopped   bool
+}
+
+func (e *memoryEvent) Type() string {
+	return e.eventType
+}
+
+func (e *memoryEvent) Target() Node {
+	if e.target == nil || e.target.nodeType == 0 {
+		return nil
+	}
+	return e.target
+}
+
+func (e *memoryEvent) CurrentTarget() Node {
+	if e.current == nil {
+		return nil
+	}
+	return e.current
+}
+
+func (e *memoryEvent) PreventDefault() {
+	e.prevented = true
+}
+
+func (e *memoryEvent) DefaultPrevented() bool {
+	return e.prevented
+}
+
+func (e *memoryEvent) StopPropagation() {
+	e.stopped = true
+}
+
+func (e *memoryEvent) PropagationStopped() bool {
+	return e.stopped
+}
+
+func (e *memoryEvent) Key() string {
+	return e.key
+}
+
+func (e *memoryEvent) Modifiers() Modifiers {
+	return e.modifiers
+}
+
+func (e *memoryEvent) Value() string {
+	if e.target == nil || e.target.nodeType != ElementNode {
+		return ""
+	}
+	return e.target.Value()
+}
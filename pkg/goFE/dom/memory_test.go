@@ -0,0 +1,146 @@
+package dom
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemory_InnerHTML(t *testing.T) {
+	m := NewMemory()
+	root := m.GetElementByID("root")
+	html := `<ul id="list"><li>a &amp; b</li><li class="x">c</li></ul><svg viewBox="0 0 1 1"><linearGradient id="g"/></svg>`
+	root.SetInnerHTML(html)
+	if got := root.InnerHTML(); got != html {
+		t.Fatalf("InnerHTML() = %q, want %q", got, html)
+	}
+	list := m.GetElementByID("list")
+	if list == nil || list.NodeName() != "UL" || list.ChildCount() != 2 {
+		t.Fatalf("unexpected list element %v", list)
+	}
+	if gradient := m.GetElementByID("g"); gradient.NodeName() != "linearGradient" {
+		t.Fatalf("SVG element name = %q", gradient.NodeName())
+	}
+
+	list.ChildAt(1).SetOuterHTML(`<li>d</li><li>e</li>`)
+	if got := list.TextContent(); got != "a & bde" {
+		t.Fatalf("TextContent() after SetOuterHTML = %q", got)
+	}
+	list.InsertBefore(list.ChildAt(2), list.ChildAt(0))
+	if got := list.InnerHTML(); got != `<li>e</li><li>a &amp; b</li><li>d</li>` {
+		t.Fatalf("InnerHTML() after move = %q", got)
+	}
+}
+
+func TestMemory_Dispatch(t *testing.T) {
+	m := NewMemory()
+	m.GetElementByID("root").SetInnerHTML(`<form id="form"><div id="outer"><button id="button">Go</button></div></form>`)
+
+	var calls []string
+	record := func(name string) func(Event) {
+		return func(e Event) {
+			calls = append(calls, name+":"+e.Type())
+		}
+	}
+	outer := m.GetElementByID("outer")
+	button := m.GetElementByID("button")
+	m.AddWindowListener("click", record("window"))
+	outer.AddEventListener("click", true, record("outer-capture"))
+	outer.AddEventListener("click", false, record("outer"))
+	remove := button.AddEventListener("click", false, record("button"))
+	m.GetElementByID("form").AddEventListener("submit", false, record("form"))
+
+	m.Dispatch(button, "click")
+	want := "outer-capture:click button:click outer:click window:click form:submit"
+	if got := strings.Join(calls, " "); got != want {
+		t.Fatalf("calls = %q, want %q", got, want)
+	}
+
+	calls = nil
+	remove()
+	outer.AddEventListener("click", false, func(e Event) {
+		e.StopPropagation()
+		e.PreventDefault()
+	})
+	m.Dispatch(button, "click")
+	want = "outer-capture:click outer:click"
+	if got := strings.Join(calls, " "); got != want {
+		t.Fatalf("calls after stopPropagation = %q, want %q", got, want)
+	}
+}
+
+func TestMemory_FormControls(t *testing.T) {
+	m := NewMemory()
+	m.GetElementByID("root").SetInnerHTML(`<input id="a" type="radio" name="r" checked><input id="b" type="radio" name="r">` +
+		`<input id="c" type="checkbox"><select id="s"><option>x</option><option value="y" selected>Y</option></select>`)
+
+	var changes []string
+	m.GetElementByID("root").AddEventListener("change", false, func(e Event) {
+		changes = append(changes, e.Target().ID())
+	})
+	m.Dispatch(m.GetElementByID("b"), "click")
+	if m.GetElementByID("a").Checked() || !m.GetElementByID("b").Checked() {
+		t.Fatal("clicking a radio button should uncheck the rest of its group")
+	}
+	m.Dispatch(m.GetElementByID("b"), "click")
+	checkbox := m.GetElementByID("c")
+	checkbox.AddEventListener("click", false, func(e Event) { e.PreventDefault() })
+	m.Dispatch(checkbox, "click")
+	if checkbox.Checked() {
+		t.Fatal("a prevented click should not toggle a checkbox")
+	}
+	if strings.Join(changes, " ") != "b" {
+		t.Fatalf("change fired for %q, want only the radio button being checked", changes)
+	}
+	selectElement := m.GetElementByID("s")
+	if selectElement.Value() != "y" {
+		t.Fatalf("select Value() = %q, want y", selectElement.Value())
+	}
+	selectElement.SetValue("x")
+	if selectElement.Value() != "x" {
+		t.Fatalf("select Value() after SetValue = %q, want x", selectElement.Value())
+	}
+}
+
+func TestMemory_History(t *testing.T) {
+	m := NewMemory()
+	var paths []string
+	m.AddWindowListener("popstate", func(Event) {
+		paths = append(paths, m.Location().Pathname)
+	})
+	m.PushState("/about")
+	m.PushState("contact?x=1#top")
+	if loc := m.Location(); loc.Pathname != "/contact" || loc.Search != "?x=1" || loc.Hash != "#top" {
+		t.Fatalf("Location() = %+v", loc)
+	}
+	m.Back()
+	m.Back()
+	m.Forward()
+	if got := strings.Join(paths, " "); got != "/about / /about" {
+		t.Fatalf("popstate paths = %q", got)
+	}
+}
+
+func TestMemory_Storage(t *testing.T) {
+	m := NewMemory()
+	var changed []string
+	m.AddWindowListener("storage", func(e Event) {
+		changed = append(changed, e.Key())
+	})
+	m.LocalStorage().SetItem("theme", "dark")
+	m.SessionStorage().SetItem("theme", "light")
+	if value, _ := m.LocalStorage().GetItem("theme"); value != "dark" {
+		t.Fatalf("local theme = %q", value)
+	}
+	if len(changed) != 0 {
+		t.Fatal("storage fired for a change made by this tab")
+	}
+
+	m.SetItemFromAnotherTab("theme", "solarized")
+	if value, _ := m.LocalStorage().GetItem("theme"); value != "solarized" || len(changed) != 1 || changed[0] != "theme" {
+		t.Fatalf("after another tab: value %q, events %v", value, changed)
+	}
+	m.LocalStorage().RemoveItem("theme")
+	if _, ok := m.LocalStorage().GetItem("theme"); ok {
+		t.Fatal("removed item still present")
+	}
+}
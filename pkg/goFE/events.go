@@ -0,0 +1,254 @@
+package goFE
+
+import (
+	"sync"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+// delegatedHandler is a handler registered for one event type on one element.
+type delegatedHandler struct {
+	owner   uuid.UUID
+	handler func(event dom.Event)
+	// release frees whatever the handler holds on to (such as a js.Func) when
+	// it is replaced or its owner is torn down. resource is that thing, and
+	// sameResource reports whether another handler's resource is the same,
+	// so registering it again does not release it.
+	release      func()
+	resource     interface{}
+	sameResource func(other interface{}) bool
+}
+
+func (h delegatedHandler) free() {
+	if h.release != nil {
+		h.release()
+	}
+}
+
+// eventRegistry holds every delegated handler of a document. Only one real
+// DOM listener per event type is added, on the root element; events are
+// dispatched by walking from event.target up to the root.
+type eventRegistry struct {
+	lock sync.Mutex
+	root dom.Node
+	// handlers maps event type -> element id -> handler.
+	handlers map[string]map[string]delegatedHandler
+	// rootListeners removes the single root listener for each event type.
+	rootListeners map[string]func()
+	// portals holds the containers portals render into outside the root,
+	// which get root listeners of their own, by container id.
+	portals map[string]*portalRoot
+	// owned maps a component id to the element ids/event types it registered.
+	owned map[uuid.UUID]map[string][]string
+	// owner is the component whose InitEventListeners is currently running.
+	owner uuid.UUID
+}
+
+func newEventRegistry() *eventRegistry {
+	return &eventRegistry{
+		handlers:      make(map[string]map[string]delegatedHandler),
+		rootListeners: make(map[string]func()),
+		portals:       make(map[string]*portalRoot),
+		owned:         make(map[uuid.UUID]map[string][]string),
+	}
+}
+
+// attach sets the element the registry delegates from and adds root
+// listeners for any event types registered before the document mounted.
+func (r *eventRegistry) attach(root dom.Node) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.root = root
+	for event := range r.handlers {
+		r.ensureRootListener(event)
+	}
+}
+
+// detach removes the root and portal listeners and releases every handler,
+// once the document is unmounted.
+func (r *eventRegistry) detach() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, remove := range r.rootListeners {
+		remove()
+	}
+	for _, portal := range r.portals {
+		for _, remove := range portal.listeners {
+			remove()
+		}
+	}
+	for _, byID := range r.handlers {
+		for _, handler := range byID {
+			handler.free()
+		}
+	}
+	r.root = nil
+	r.handlers = make(map[string]map[string]delegatedHandler)
+	r.rootListeners = make(map[string]func())
+	r.portals = make(map[string]*portalRoot)
+	r.owned = make(map[uuid.UUID]map[string][]string)
+}
+
+// register adds handler for event on the element with the given id, owned
+// by whichever component is currently initialising its listeners. A handler
+// previously registered for the same element and event is released.
+func (r *eventRegistry) register(id string, event string, handler delegatedHandler) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	byID, ok := r.handlers[event]
+	if !ok {
+		byID = make(map[string]delegatedHandler)
+		r.handlers[event] = byID
+	}
+	if previous, ok := byID[id]; ok && (handler.sameResource == nil || !handler.sameResource(previous.resource)) {
+		previous.free()
+	}
+	handler.owner = r.owner
+	byID[id] = handler
+	if r.owner != uuid.Nil {
+		if _, ok := r.owned[r.owner]; !ok {
+			r.owned[r.owner] = make(map[string][]string)
+		}
+		r.owned[r.owner][id] = appendUnique(r.owned[r.owner][id], event)
+	}
+	r.ensureRootListener(event)
+}
+
+// releaseOwner unregisters and releases every handler registered while the
+// given component was initialising its listeners.
+func (r *eventRegistry) releaseOwner(owner uuid.UUID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for id, events := range r.owned[owner] {
+		for _, event := range events {
+			handler, ok := r.handlers[event][id]
+			if !ok || handler.owner != owner {
+				continue
+			}
+			handler.free()
+			delete(r.handlers[event], id)
+		}
+	}
+	delete(r.owned, owner)
+}
+
+// setOwner records the component that subsequent registrations belong to.
+func (r *eventRegistry) setOwner(owner uuid.UUID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.owner = owner
+}
+
+// ensureRootListener must be called with the lock held.
+func (r *eventRegistry) ensureRootListener(event string) {
+	for _, portal := range r.portals {
+		portal.listen(r, event)
+	}
+	if _, ok := r.rootListeners[event]; ok || r.root == nil {
+		return
+	}
+	// Events that do not bubble are caught in the capture phase and only
+	// dispatched to the handler registered on the target itself
+	r.rootListeners[event] = r.root.AddEventListener(event, !dom.Bubbles(event), func(e dom.Event) {
+		r.dispatch(event, e)
+	})
+}
+
+// portalRoot is a container a portal renders into. Events inside it bubble
+// on to the portal's placeholder in the logical parent once they leave it.
+type portalRoot struct {
+	container   dom.Node
+	placeholder func() dom.Node
+	listeners   map[string]func()
+}
+
+// listen must be called with the registry's lock held.
+func (p *portalRoot) listen(r *eventRegistry, event string) {
+	if _, ok := p.listeners[event]; ok {
+		return
+	}
+	p.listeners[event] = p.container.AddEventListener(event, !dom.Bubbles(event), func(e dom.Event) {
+		r.dispatch(event, e)
+	})
+}
+
+// addPortal delegates events from container, an element outside the root,
+// continuing from the node placeholder returns once they bubble out of it.
+func (r *eventRegistry) addPortal(container dom.Node, placeholder func() dom.Node) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	portal := &portalRoot{container: container, placeholder: placeholder, listeners: make(map[string]func())}
+	r.portals[container.ID()] = portal
+	for event := range r.handlers {
+		portal.listen(r, event)
+	}
+}
+
+// removePortal stops delegating events from the container with the given id.
+func (r *eventRegistry) removePortal(id string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if portal, ok := r.portals[id]; ok {
+		for _, remove := range portal.listeners {
+			remove()
+		}
+		delete(r.portals, id)
+	}
+}
+
+// dispatch invokes the handlers for event from its target up to the root,
+// with the element each handler was registered on as the current target.
+// Events leaving a portal's container continue from its placeholder.
+func (r *eventRegistry) dispatch(event string, e dom.Event) {
+	for node := e.Target(); node != nil && !node.IsSameNode(r.root); node = r.parentOf(node) {
+		if id := node.ID(); id != "" {
+			r.lock.Lock()
+			handler, ok := r.handlers[event][id]
+			r.lock.Unlock()
+			if ok {
+				recordEvent(handler.owner, event, e)
+				guard(handler.owner, func() {
+					handler.handler(&delegatedEvent{Event: e, current: node})
+				})
+				if e.PropagationStopped() {
+					return
+				}
+			}
+		}
+		if !dom.Bubbles(event) {
+			return
+		}
+	}
+}
+
+// parentOf returns the node an event bubbles to from node.
+func (r *eventRegistry) parentOf(node dom.Node) dom.Node {
+	r.lock.Lock()
+	portal, ok := r.portals[node.ID()]
+	r.lock.Unlock()
+	if ok && node.IsSameNode(portal.container) {
+		return portal.placeholder()
+	}
+	return node.ParentNode()
+}
+
+// delegatedEvent reports the element a delegated handler was registered on
+// as the current target, rather than the root the real listener sits on.
+type delegatedEvent struct {
+	dom.Event
+	current dom.Node
+}
+
+func (e *delegatedEvent) CurrentTarget() dom.Node {
+	return e.current
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
@@ -0,0 +1,134 @@
+package goFE
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+// newTestRegistry returns a registry delegating from #root of a fresh DOM
+// holding markup.
+func newTestRegistry(markup string) (*eventRegistry, *dom.Memory) {
+	memory := dom.NewMemory()
+	root := memory.GetElementByID("root")
+	root.SetInnerHTML(markup)
+	registry := newEventRegistry()
+	registry.attach(root)
+	return registry, memory
+}
+
+// recordingHandler appends name to calls when invoked, and counts the times
+// it is released in released.
+func recordingHandler(name string, calls *[]string, released *int) delegatedHandler {
+	return delegatedHandler{
+		handler: func(dom.Event) { *calls = append(*calls, name) },
+		release: func() { *released++ },
+	}
+}
+
+func TestEventRegistry_ReleasesOnTeardown(t *testing.T) {
+	registry, memory := newTestRegistry(`<button id="a"></button><button id="b"></button>`)
+	owner, other := uuid.New(), uuid.New()
+	var calls []string
+	var releasedA, releasedB int
+
+	registry.setOwner(owner)
+	registry.register("a", "click", recordingHandler("a", &calls, &releasedA))
+	registry.register("a", "keydown", recordingHandler("a", &calls, &releasedA))
+	registry.setOwner(other)
+	registry.register("b", "click", recordingHandler("b", &calls, &releasedB))
+	registry.setOwner(uuid.Nil)
+
+	registry.releaseOwner(owner)
+	if releasedA != 2 || releasedB != 0 {
+		t.Fatalf("released a %d times and b %d times, want 2 and 0", releasedA, releasedB)
+	}
+	if _, ok := registry.owned[owner]; ok || len(registry.handlers["click"]) != 1 || len(registry.handlers["keydown"]) != 0 {
+		t.Fatal("the torn down owner's handlers are still registered")
+	}
+	memory.Dispatch(memory.GetElementByID("a"), "click")
+	memory.Dispatch(memory.GetElementByID("b"), "click")
+	if strings.Join(calls, " ") != "b" {
+		t.Fatalf("calls = %v, want only b", calls)
+	}
+
+	registry.detach()
+	if releasedB != 1 || len(registry.handlers) != 0 {
+		t.Fatal("detach did not release the remaining handlers")
+	}
+	memory.Dispatch(memory.GetElementByID("b"), "click")
+	if len(calls) != 1 {
+		t.Fatal("a handler ran after detach")
+	}
+}
+
+func TestEventRegistry_ReplacesHandler(t *testing.T) {
+	registry, memory := newTestRegistry(`<button id="a"></button>`)
+	var calls []string
+	var releasedFirst, releasedSecond int
+
+	registry.register("a", "click", recordingHandler("first", &calls, &releasedFirst))
+	registry.register("a", "click", recordingHandler("second", &calls, &releasedSecond))
+	memory.Dispatch(memory.GetElementByID("a"), "click")
+	if strings.Join(calls, " ") != "second" || releasedFirst != 1 || releasedSecond != 0 {
+		t.Fatalf("calls = %v, released %d and %d", calls, releasedFirst, releasedSecond)
+	}
+
+	// Registering the same resource again must not free it
+	resource := new(int)
+	same := func(other interface{}) bool { return other == resource }
+	var releasedShared int
+	shared := delegatedHandler{handler: func(dom.Event) {}, release: func() { releasedShared++ }, resource: resource, sameResource: same}
+	registry.register("a", "click", shared)
+	registry.register("a", "click", shared)
+	if releasedShared != 0 || releasedSecond != 1 {
+		t.Fatalf("released the shared resource %d times and second %d times, want 0 and 1", releasedShared, releasedSecond)
+	}
+}
+
+func TestEventRegistry_DispatchOrder(t *testing.T) {
+	registry, memory := newTestRegistry(`<div id="outer"><div id="inner"><button id="button"></button></div></div>`)
+	var calls []string
+	var released int
+	for _, id := range []string{"outer", "inner", "button"} {
+		registry.register(id, "click", recordingHandler(id+":click", &calls, &released))
+		registry.register(id, "focus", recordingHandler(id+":focus", &calls, &released))
+	}
+	// A capturing listener on the root runs before every delegated handler,
+	// which run from the root's bubble phase
+	memory.GetElementByID("root").AddEventListener("click", true, func(dom.Event) {
+		calls = append(calls, "root:capture")
+	})
+
+	memory.Dispatch(memory.GetElementByID("button"), "click")
+	memory.Dispatch(memory.GetElementByID("button"), "focus")
+	memory.Dispatch(memory.GetElementByID("inner"), "focus")
+	want := "root:capture button:click inner:click outer:click button:focus inner:focus"
+	if got := strings.Join(calls, " "); got != want {
+		t.Fatalf("calls = %q, want %q", got, want)
+	}
+}
+
+func TestEventRegistry_StopPropagation(t *testing.T) {
+	registry, memory := newTestRegistry(`<div id="outer"><button id="button"></button></div>`)
+	var calls []string
+	var current string
+	registry.register("outer", "click", delegatedHandler{handler: func(dom.Event) {
+		calls = append(calls, "outer")
+	}})
+	registry.register("button", "click", delegatedHandler{handler: func(e dom.Event) {
+		calls = append(calls, "button")
+		current = e.CurrentTarget().ID()
+		e.StopPropagation()
+	}})
+
+	memory.Dispatch(memory.GetElementByID("button"), "click")
+	if strings.Join(calls, " ") != "button" {
+		t.Fatalf("calls = %v, want the event to stop at button", calls)
+	}
+	if current != "button" {
+		t.Fatalf("CurrentTarget = %q, want the element the handler was registered on", current)
+	}
+}
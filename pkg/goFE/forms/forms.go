@@ -0,0 +1,285 @@
+// Package forms binds form inputs to the fields of a goFE State holding a
+// struct, and validates them with the rules in the struct's validate tags:
+//
+//	type signup struct {
+//		Email string `validate:"required,email"`
+//		Name  string `validate:"required,min=2,max=100"`
+//	}
+//
+//	s.state, _ = goFE.NewState[signup](s, &signup{})
+//	s.form = forms.New(s, s.state)
+//
+//	func (s *Signup) InitEventListeners() {
+//		s.form.Bind(s.emailID, "Email")
+//		s.form.Bind(s.nameID, "Name")
+//		s.form.OnSubmit(s.formID, s.register)
+//	}
+//
+// Render reads the values back with Value and the messages to show with
+// Error, which only reports a field once it has been touched or the form
+// submitted.
+package forms
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+// status is the part of a form's state that is not the value itself.
+type status struct {
+	Touched   map[string]bool
+	Submitted bool
+}
+
+// Form binds the inputs of a component to the fields of a State holding a
+// struct. Fields are the exported, top-level fields of the struct, named as
+// in Go; strings, booleans and numbers can be bound.
+type Form[T any] struct {
+	state     *goFE.State[T]
+	initial   T
+	status    *goFE.State[status]
+	setStatus func(*status)
+
+	lock sync.Mutex
+	// bound holds the fields bound to an input.
+	bound map[string]bool
+}
+
+// New creates a form for state, owned by component. The value state holds
+// now is what Reset returns to and Dirty compares against.
+func New[T any](component goFE.Component, state *goFE.State[T]) *Form[T] {
+	value := state.Get()
+	if reflect.TypeOf(value).Elem().Kind() != reflect.Struct {
+		panic("forms: the state of a form must hold a struct")
+	}
+	f := &Form[T]{state: state, initial: *value, bound: map[string]bool{}}
+	f.status, f.setStatus = goFE.NewState[status](component, &status{Touched: map[string]bool{}})
+	return f
+}
+
+// Bind makes the input, select or textarea with the given id show and edit
+// field. The field is updated on input and change (from Checked for
+// checkboxes bound to a bool), and marked touched on blur. Call it from the
+// component's InitEventListeners.
+func (f *Form[T]) Bind(id uuid.UUID, field string) {
+	kind := f.field(*f.state.Get(), field).Kind()
+	f.lock.Lock()
+	f.bound[field] = true
+	f.lock.Unlock()
+	update := func(event dom.Event) {
+		raw := event.Value()
+		if target := event.Target(); kind == reflect.Bool && target != nil {
+			raw = strconv.FormatBool(target.Checked())
+		}
+		f.state.Update(func(prev *T) *T {
+			next := *prev
+			if err := parseValue(f.field(&next, field), raw); err != nil {
+				// Keep the previous value rather than storing garbage; the
+				// input still shows what was typed
+				return prev
+			}
+			return &next
+		})
+	}
+	document := goFE.GetDocument()
+	document.On(id, "input", update)
+	document.On(id, "change", update)
+	document.On(id, "blur", func(dom.Event) {
+		f.touch(field)
+	})
+}
+
+// OnSubmit handles the submission of the form element with the given id:
+// the browser's own submission is prevented, every bound field is marked
+// touched, and submit is called with a copy of the value, only if it is
+// valid. submit runs in a goroutine of its own, so it may block, e.g. on a
+// request. Call it from the component's InitEventListeners.
+func (f *Form[T]) OnSubmit(id uuid.UUID, submit func(value T)) {
+	goFE.GetDocument().On(id, "submit", func(event dom.Event) {
+		event.PreventDefault()
+		f.lock.Lock()
+		fields := make([]string, 0, len(f.bound))
+		for field := range f.bound {
+			fields = append(fields, field)
+		}
+		f.lock.Unlock()
+		f.status.Update(func(prev *status) *status {
+			return &status{Touched: withTouched(prev.Touched, fields...), Submitted: true}
+		})
+		// Read the value once the inputs that fired before submit are applied
+		goFE.Atomic(func() {
+			value := *f.state.Get()
+			if len(Validate(&value)) == 0 {
+				// Off the scheduler, which submit must not hold up
+				go submit(value)
+			}
+		})
+	})
+}
+
+// Value returns field formatted for an input's value attribute.
+func (f *Form[T]) Value(field string) string {
+	return formatValue(f.field(*f.state.Get(), field))
+}
+
+// Checked reports whether a bool field is set, for a checkbox's checked
+// attribute.
+func (f *Form[T]) Checked(field string) bool {
+	value := f.field(*f.state.Get(), field)
+	return value.Kind() == reflect.Bool && value.Bool()
+}
+
+// Errors returns the rules the current value breaks, whether or not they
+// should be shown yet.
+func (f *Form[T]) Errors() Errors {
+	return Validate(f.state.Get())
+}
+
+// Valid reports whether the current value breaks no rule.
+func (f *Form[T]) Valid() bool {
+	return len(f.Errors()) == 0
+}
+
+// Error returns the first message for field, once the field has been
+// touched or the form submitted, and "" otherwise. Render it next to the
+// input, along with aria-invalid.
+func (f *Form[T]) Error(field string) string {
+	if !f.Touched(field) && !f.Submitted() {
+		return ""
+	}
+	if messages := f.Errors()[field]; len(messages) > 0 {
+		return messages[0]
+	}
+	return ""
+}
+
+// Touched reports whether the input bound to field has lost focus since the
+// form was created or reset.
+func (f *Form[T]) Touched(field string) bool {
+	return f.status.Get().Touched[field]
+}
+
+// Dirty reports whether field differs from its initial value.
+func (f *Form[T]) Dirty(field string) bool {
+	return !reflect.DeepEqual(f.field(*f.state.Get(), field).Interface(), f.field(f.initial, field).Interface())
+}
+
+// Submitted reports whether submitting has been attempted since the form was
+// created or reset.
+func (f *Form[T]) Submitted() bool {
+	return f.status.Get().Submitted
+}
+
+// Reset sets the value back to its initial one and clears the touched and
+// submitted status.
+func (f *Form[T]) Reset() {
+	initial := f.initial
+	f.state.Update(func(*T) *T {
+		return &initial
+	})
+	f.setStatus(&status{Touched: map[string]bool{}})
+}
+
+// touch marks field touched, on the scheduler so it is not lost to a
+// concurrent change of the status.
+func (f *Form[T]) touch(field string) {
+	f.status.Update(func(prev *status) *status {
+		if prev.Touched[field] {
+			return prev
+		}
+		return &status{Touched: withTouched(prev.Touched, field), Submitted: prev.Submitted}
+	})
+}
+
+// withTouched returns a copy of touched with fields added.
+func withTouched(touched map[string]bool, fields ...string) map[string]bool {
+	next := make(map[string]bool, len(touched)+len(fields))
+	for field, ok := range touched {
+		next[field] = ok
+	}
+	for _, field := range fields {
+		next[field] = true
+	}
+	return next
+}
+
+// field returns the named field of value, a struct or pointer to one. A
+// pointer gives a settable field.
+func (f *Form[T]) field(value any, name string) reflect.Value {
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	field := v.FieldByName(name)
+	if !field.IsValid() {
+		panic("forms: " + v.Type().String() + " has no field " + name)
+	}
+	return field
+}
+
+// formatValue formats a string, bool or number field as text.
+func formatValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	}
+	return ""
+}
+
+// parseValue sets a string, bool or number field from text. Empty text sets
+// numbers to zero.
+func parseValue(field reflect.Value, raw string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if raw == "" {
+			raw = "0"
+		}
+		parsed, err := strconv.ParseInt(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if raw == "" {
+			raw = "0"
+		}
+		parsed, err := strconv.ParseUint(raw, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		if raw == "" {
+			raw = "0"
+		}
+		parsed, err := strconv.ParseFloat(raw, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(parsed)
+	default:
+		return &reflect.ValueError{Method: "forms.Bind", Kind: field.Kind()}
+	}
+	return nil
+}
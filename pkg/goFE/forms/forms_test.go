@@ -0,0 +1,164 @@
+package forms_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/forms"
+	"github.com/cstevenson98/goFE/pkg/goFE/html"
+	gofetest "github.com/cstevenson98/goFE/pkg/goFE/testing"
+	"github.com/cstevenson98/goFE/pkg/shared"
+	"github.com/google/uuid"
+)
+
+type signupValue struct {
+	Email string `validate:"required,email"`
+	Name  string `validate:"required,min=2,max=100"`
+	Age   int    `validate:"min=18"`
+	Terms bool   `validate:"required"`
+}
+
+type signup struct {
+	id, formID, emailID, nameID, ageID, termsID uuid.UUID
+	state                                       *goFE.State[signupValue]
+	form                                        *forms.Form[signupValue]
+	submitted                                   chan signupValue
+}
+
+func newSignup() *signup {
+	s := &signup{id: uuid.New(), formID: uuid.New(), emailID: uuid.New(), nameID: uuid.New(), ageID: uuid.New(), termsID: uuid.New()}
+	// Unbuffered, so submitting blocks until the test receives the value
+	s.submitted = make(chan signupValue)
+	s.state, _ = goFE.NewState[signupValue](s, &signupValue{})
+	s.form = forms.New(s, s.state)
+	return s
+}
+
+func (s *signup) Render() string {
+	field := func(id uuid.UUID, name string) html.Node {
+		return html.Group{
+			html.Input(html.ID(id), html.Value(s.form.Value(name)), html.Aria("invalid", boolString(s.form.Error(name) != ""))),
+			html.If(s.form.Error(name) != "", html.P(html.Class("error"), html.Text(name+" "+s.form.Error(name)))),
+		}
+	}
+	return html.Render(html.Form(html.ID(s.formID),
+		html.Div(html.ID(s.id),
+			field(s.emailID, "Email"),
+			field(s.nameID, "Name"),
+			field(s.ageID, "Age"),
+			html.Input(html.ID(s.termsID), html.Type("checkbox"), html.Checked(s.form.Checked("Terms"))),
+		),
+	))
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func (s *signup) GetID() uuid.UUID              { return s.id }
+func (s *signup) GetChildren() []goFE.Component { return nil }
+func (s *signup) InitEventListeners() {
+	s.form.Bind(s.emailID, "Email")
+	s.form.Bind(s.nameID, "Name")
+	s.form.Bind(s.ageID, "Age")
+	s.form.Bind(s.termsID, "Terms")
+	s.form.OnSubmit(s.formID, func(value signupValue) {
+		s.submitted <- value
+	})
+}
+
+func TestForm_BindValidateSubmit(t *testing.T) {
+	s := newSignup()
+	h := gofetest.Mount(t, s)
+	if len(h.QueryAll("p.error")) != 0 {
+		t.Fatal("errors shown before the user did anything")
+	}
+
+	h.Input(s.emailID.String(), "not an email")
+	if len(h.QueryAll("p.error")) != 0 || !s.form.Dirty("Email") || s.form.Dirty("Name") {
+		t.Fatal("error shown before the field was left, or wrong dirty status")
+	}
+	h.Fire(s.emailID.String(), "blur")
+	if got := h.Query("p.error").TextContent(); got != "Email must be a valid email address" {
+		t.Fatalf("error after blur = %q", got)
+	}
+	if invalid, _ := h.Element(s.emailID.String()).GetAttribute("aria-invalid"); invalid != "true" {
+		t.Fatal("invalid input not marked aria-invalid")
+	}
+
+	h.Submit(s.formID.String())
+	if len(s.submitted) != 0 || len(h.QueryAll("p.error")) != 2 {
+		t.Fatalf("submitted while invalid, errors shown:\n%s", h.HTML())
+	}
+	for _, field := range []string{"Email", "Name", "Age", "Terms"} {
+		if !s.form.Touched(field) {
+			t.Fatalf("%s not marked touched by submitting", field)
+		}
+	}
+
+	h.Input(s.emailID.String(), "ada@example.com")
+	h.Input(s.nameID.String(), "Ada")
+	h.Input(s.ageID.String(), "36")
+	h.Element(s.termsID.String()).SetChecked(true)
+	h.Fire(s.termsID.String(), "change")
+	h.Submit(s.formID.String())
+	want := signupValue{Email: "ada@example.com", Name: "Ada", Age: 36, Terms: true}
+	// The blocked submit does not hold up rendering
+	h.Input(s.nameID.String(), "Grace")
+	if got := h.Element(s.nameID.String()).Value(); got != "Grace" || s.form.Value("Name") != "Grace" {
+		t.Fatalf("input after submitting rendered %q", got)
+	}
+	select {
+	case got := <-s.submitted:
+		if got != want {
+			t.Fatalf("submitted %+v, want %+v", got, want)
+		}
+	case <-time.After(gofetest.WaitTimeout):
+		t.Fatal("submit was not called")
+	}
+
+	s.form.Reset()
+	h.Flush()
+	if s.form.Submitted() || s.form.Value("Email") != "" || h.Element(s.emailID.String()).Value() != "" {
+		t.Fatal("Reset kept the previous value or status")
+	}
+}
+
+func TestValidate_Rules(t *testing.T) {
+	errs := forms.Validate(shared.CreateUserRequest{Email: "ada@example", Name: "A"})
+	if len(errs) != 2 || errs["Email"][0] != "must be a valid email address" || errs["Name"][0] != "must be at least 2 characters long" {
+		t.Fatalf("errors = %v", errs)
+	}
+	if errs := forms.Validate(&shared.CreateUserRequest{Email: "ada@example.com", Name: "Ada"}); len(errs) != 0 {
+		t.Fatalf("valid request reported %v", errs)
+	}
+}
+
+func TestValidate_CustomRule(t *testing.T) {
+	forms.RegisterRule("even", func(value reflect.Value, _ string) string {
+		if value.Int()%2 != 0 {
+			return "must be even"
+		}
+		return ""
+	})
+	type pair struct {
+		Count int    `validate:"even,max=10"`
+		Mode  string `validate:"oneof=fast slow"`
+		Tags  []int  `validate:"len=2"`
+	}
+	errs := forms.Validate(pair{Count: 13, Mode: "medium", Tags: []int{1}})
+	if got := errs["Count"]; len(got) != 2 || got[0] != "must be even" || got[1] != "must be at most 10" {
+		t.Fatalf("Count errors = %v", got)
+	}
+	if got := errs["Mode"]; len(got) != 1 || got[0] != "must be one of fast, slow" {
+		t.Fatalf("Mode errors = %v", got)
+	}
+	if got := errs["Tags"]; len(got) != 1 || got[0] != "must have exactly 2 items" {
+		t.Fatalf("Tags errors = %v", got)
+	}
+}
@@ -0,0 +1,196 @@
+package forms
+
+import (
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Errors maps struct field names to the messages of the rules they break.
+type Errors map[string][]string
+
+// Rule checks value against a validation rule, given the parameter written
+// after "=" in the tag (e.g. "2" for min=2). It returns a message describing
+// the problem, or "" if the value is valid.
+type Rule func(value reflect.Value, param string) string
+
+var rulesLock sync.RWMutex
+
+var rules = map[string]Rule{
+	"required": required,
+	"email":    email,
+	"url":      isURL,
+	"min":      minimum,
+	"max":      maximum,
+	"len":      length,
+	"oneof":    oneOf,
+	"numeric":  numeric,
+}
+
+// RegisterRule adds a rule usable in validate tags under name, or replaces
+// a built-in one.
+func RegisterRule(name string, rule Rule) {
+	rulesLock.Lock()
+	defer rulesLock.Unlock()
+	rules[name] = rule
+}
+
+// Validate checks the fields of a struct, or pointer to one, against the
+// rules in their validate tags, such as
+//
+//	Email string `validate:"required,email"`
+//	Name  string `validate:"required,min=2,max=100"`
+//
+// The built-in rules are required, email, url, min, max and len (lengths of
+// strings and slices, values of numbers), oneof (space-separated values) and
+// numeric. A field left empty is only checked by required. Only exported,
+// top-level fields are validated.
+func Validate(value any) Errors {
+	errs := make(Errors)
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return errs
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return errs
+	}
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag, ok := field.Tag.Lookup("validate")
+		if !ok || !field.IsExported() {
+			continue
+		}
+		if messages := validateField(v.Field(i), tag); len(messages) > 0 {
+			errs[field.Name] = messages
+		}
+	}
+	return errs
+}
+
+func validateField(value reflect.Value, tag string) []string {
+	var messages []string
+	empty := value.IsZero()
+	for _, clause := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(clause), "=")
+		if name == "" || name != "required" && empty {
+			continue
+		}
+		rulesLock.RLock()
+		rule, ok := rules[name]
+		rulesLock.RUnlock()
+		if !ok {
+			messages = append(messages, "has unknown validation rule "+name)
+			continue
+		}
+		if message := rule(value, param); message != "" {
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+func required(value reflect.Value, _ string) string {
+	if value.IsZero() {
+		return "is required"
+	}
+	return ""
+}
+
+// email accepts plain addresses whose domain has a dot, which rules out
+// names and local hosts that parse as RFC 5322 addresses.
+func email(value reflect.Value, _ string) string {
+	address, err := mail.ParseAddress(value.String())
+	if value.Kind() != reflect.String || err != nil || address.Address != value.String() {
+		return "must be a valid email address"
+	}
+	if _, domain, _ := strings.Cut(address.Address, "@"); !strings.Contains(strings.Trim(domain, "."), ".") {
+		return "must be a valid email address"
+	}
+	return ""
+}
+
+func isURL(value reflect.Value, _ string) string {
+	parsed, err := url.Parse(value.String())
+	if value.Kind() != reflect.String || err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return "must be a valid URL"
+	}
+	return ""
+}
+
+func numeric(value reflect.Value, _ string) string {
+	if value.Kind() != reflect.String {
+		return ""
+	}
+	if _, err := strconv.ParseFloat(value.String(), 64); err != nil {
+		return "must be a number"
+	}
+	return ""
+}
+
+func oneOf(value reflect.Value, param string) string {
+	options := strings.Fields(param)
+	actual := formatValue(value)
+	for _, option := range options {
+		if option == actual {
+			return ""
+		}
+	}
+	return "must be one of " + strings.Join(options, ", ")
+}
+
+// size returns the length of strings (in characters), slices and maps, or
+// the value of numbers, with the unit of a length.
+func size(value reflect.Value) (actual float64, unit string, ok bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), "characters", true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), "items", true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), "", true
+	}
+	return 0, "", false
+}
+
+// compare checks value against the limit in param, describing a failure as
+// "must be <relation> <limit>" followed by the unit of lengths.
+func compare(value reflect.Value, param, relation string, ok func(actual, limit float64) bool) string {
+	limit, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return "has an invalid rule parameter " + param
+	}
+	actual, unit, known := size(value)
+	if !known || ok(actual, limit) {
+		return ""
+	}
+	switch unit {
+	case "characters":
+		return "must be " + relation + param + " characters long"
+	case "items":
+		return "must have " + relation + param + " items"
+	}
+	return "must be " + relation + param
+}
+
+func minimum(value reflect.Value, param string) string {
+	return compare(value, param, "at least ", func(actual, limit float64) bool { return actual >= limit })
+}
+
+func maximum(value reflect.Value, param string) string {
+	return compare(value, param, "at most ", func(actual, limit float64) bool { return actual <= limit })
+}
+
+func length(value reflect.Value, param string) string {
+	return compare(value, param, "exactly ", func(actual, limit float64) bool { return actual == limit })
+}
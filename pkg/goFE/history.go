@@ -0,0 +1,179 @@
+package goFE
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+)
+
+// defaultHistoryDepth is the number of undo steps kept when
+// HistoryOptions.Depth is not set.
+const defaultHistoryDepth = 100
+
+// HistoryOptions configures NewHistory.
+type HistoryOptions struct {
+	// Depth bounds the number of undo steps kept; 0 means 100.
+	Depth int
+	// GroupWindow merges a change into the previous history entry when it
+	// follows it within this duration, so typing a word is undone at once.
+	// 0 records every change separately.
+	GroupWindow time.Duration
+}
+
+// History records the values of a State so they can be undone and redone.
+// Changes are recorded as they are applied, so only changes made after the
+// history is created can be undone.
+type History[T any] struct {
+	state  *State[T]
+	depth  int
+	window time.Duration
+
+	lock    sync.Mutex
+	current *T
+	past    []*T
+	future  []*T
+	// restoring is set while the update queued by an Undo or Redo is
+	// applied, which is not recorded as a change.
+	restoring  bool
+	lastChange time.Time
+}
+
+// NewHistory starts recording the changes of state.
+func NewHistory[T any](state *State[T], options *HistoryOptions) *History[T] {
+	if options == nil {
+		options = &HistoryOptions{}
+	}
+	h := &History[T]{
+		state:   state,
+		depth:   options.Depth,
+		window:  options.GroupWindow,
+		current: state.Get(),
+	}
+	if h.depth <= 0 {
+		h.depth = defaultHistoryDepth
+	}
+	state.observe(h.record)
+	return h
+}
+
+// record runs on the scheduler as each change of the state is applied, so
+// the stacks always match the updates applied so far.
+func (h *History[T]) record(value *T) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.restoring {
+		h.restoring = false
+		return
+	}
+	now := time.Now()
+	grouped := h.window > 0 && !h.lastChange.IsZero() && now.Sub(h.lastChange) < h.window
+	if !grouped {
+		h.past = append(h.past, h.current)
+		if len(h.past) > h.depth {
+			h.past = h.past[len(h.past)-h.depth:]
+		}
+	}
+	h.future = nil
+	h.current = value
+	h.lastChange = now
+}
+
+// CanUndo reports whether there is a change to undo. Undo and Redo are only
+// reflected once the scheduler has applied them.
+func (h *History[T]) CanUndo() bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return len(h.past) > 0
+}
+
+// CanRedo reports whether there is an undone change to redo.
+func (h *History[T]) CanRedo() bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return len(h.future) > 0
+}
+
+// Undo sets the state back to its value before the last change, if any. It
+// is queued on the scheduler after the updates already queued, so it undoes
+// the last of them.
+func (h *History[T]) Undo() {
+	h.state.Update(func(prev *T) *T {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		if len(h.past) == 0 {
+			return h.restore(prev)
+		}
+		value := h.past[len(h.past)-1]
+		h.past = h.past[:len(h.past)-1]
+		h.future = append(h.future, h.current)
+		return h.restore(value)
+	})
+}
+
+// Redo re-applies the last undone change, if any. Like Undo, it is queued
+// on the scheduler.
+func (h *History[T]) Redo() {
+	h.state.Update(func(prev *T) *T {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+		if len(h.future) == 0 {
+			return h.restore(prev)
+		}
+		value := h.future[len(h.future)-1]
+		h.future = h.future[:len(h.future)-1]
+		h.past = append(h.past, h.current)
+		return h.restore(value)
+	})
+}
+
+// restore makes value current without recording the update that applies
+// it, and returns it. It runs on the scheduler with the lock held.
+func (h *History[T]) restore(value *T) *T {
+	h.restoring = true
+	if value != h.current {
+		h.current = value
+		// The next change starts a new entry rather than joining the group
+		h.lastChange = time.Time{}
+	}
+	return value
+}
+
+// BindShortcuts undoes on Ctrl+Z (Cmd+Z on macOS) and redoes on
+// Ctrl+Shift+Z or Ctrl+Y, while focus is inside component. The shortcuts
+// are removed when component is torn down.
+func (h *History[T]) BindShortcuts(component Component) {
+	id := component.GetID().String()
+	remove := currentDOM().AddWindowListener("keydown", func(event dom.Event) {
+		modifiers := event.Modifiers()
+		if !modifiers.Has(dom.Ctrl) && !modifiers.Has(dom.Meta) || !within(event.Target(), id) {
+			return
+		}
+		switch strings.ToLower(event.Key()) {
+		case "z":
+			if modifiers.Has(dom.Shift) {
+				h.Redo()
+			} else {
+				h.Undo()
+			}
+		case "y":
+			h.Redo()
+		default:
+			return
+		}
+		event.PreventDefault()
+	})
+	onTeardown(component, remove)
+}
+
+// within reports whether node is, or is inside, the element with the given
+// id.
+func within(node dom.Node, id string) bool {
+	for ; node != nil; node = node.ParentNode() {
+		if node.NodeType() == dom.ElementNode && node.ID() == id {
+			return true
+		}
+	}
+	return false
+}
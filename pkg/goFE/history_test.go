@@ -0,0 +1,140 @@
+package goFE
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+func TestHistory_UndoRedo(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	text, setText := NewState[string](owner, new(string))
+	defer killAllStates(owner)
+	history := NewHistory(text, &HistoryOptions{Depth: 2})
+	set := func(value string) {
+		setText(&value)
+		Flush()
+	}
+
+	set("a")
+	set("ab")
+	set("abc")
+	history.Undo()
+	Flush()
+	history.Undo()
+	Flush()
+	if *text.Get() != "a" || history.CanUndo() {
+		t.Fatalf("after undoing twice: %q, CanUndo %v", *text.Get(), history.CanUndo())
+	}
+	history.Redo()
+	Flush()
+	if *text.Get() != "ab" || !history.CanRedo() {
+		t.Fatalf("after redo: %q, CanRedo %v", *text.Get(), history.CanRedo())
+	}
+	set("abd")
+	if history.CanRedo() {
+		t.Fatal("a new change did not clear the redo stack")
+	}
+	history.Undo()
+	Flush()
+	if *text.Get() != "ab" {
+		t.Fatalf("after undoing the new change: %q", *text.Get())
+	}
+}
+
+func TestHistory_UndoRedoThroughNil(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	selected, setSelected := NewState[string](owner, nil)
+	defer killAllStates(owner)
+	history := NewHistory(selected, nil)
+
+	value := "a"
+	setSelected(&value)
+	Flush()
+	history.Undo()
+	Flush()
+	if selected.Get() != nil || history.CanUndo() || !history.CanRedo() {
+		t.Fatalf("after undoing back to nil: CanUndo %v, CanRedo %v", history.CanUndo(), history.CanRedo())
+	}
+	history.Redo()
+	Flush()
+	if selected.Get() == nil || *selected.Get() != "a" || !history.CanUndo() || history.CanRedo() {
+		t.Fatalf("after redo: CanUndo %v, CanRedo %v", history.CanUndo(), history.CanRedo())
+	}
+}
+
+func TestHistory_QueuedUndos(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	text, setText := NewState[string](owner, new(string))
+	defer killAllStates(owner)
+	history := NewHistory(text, nil)
+	for _, value := range []string{"a", "ab", "abc"} {
+		value := value
+		setText(&value)
+		Flush()
+	}
+
+	history.Undo()
+	history.Undo()
+	Flush()
+	if *text.Get() != "a" || !history.CanRedo() {
+		t.Fatalf("after two undos in one flush: %q, CanRedo %v", *text.Get(), history.CanRedo())
+	}
+	history.Redo()
+	Flush()
+	if *text.Get() != "ab" {
+		t.Fatalf("after redo: %q", *text.Get())
+	}
+
+	// An Undo queued behind a change undoes that change
+	value := "abx"
+	setText(&value)
+	history.Undo()
+	Flush()
+	if *text.Get() != "ab" || !history.CanRedo() {
+		t.Fatalf("after undoing a queued change: %q, CanRedo %v", *text.Get(), history.CanRedo())
+	}
+	history.Redo()
+	Flush()
+	if *text.Get() != "abx" || history.CanRedo() {
+		t.Fatalf("after redoing the queued change: %q, CanRedo %v", *text.Get(), history.CanRedo())
+	}
+}
+
+func TestHistory_GroupsAndShortcuts(t *testing.T) {
+	memory := dom.NewMemory()
+	owner := &selectProbe{id: uuid.New()}
+	text, setText := NewState[string](owner, new(string))
+	owner.value = func() string { return `<input id="field">` + *text.Get() }
+	SetDocument(NewDocumentWithDOM([]Component{owner}, memory))
+	defer SetDocument(nil)
+	document.Init()
+	defer killAllStates(owner)
+
+	history := NewHistory(text, &HistoryOptions{GroupWindow: time.Hour})
+	history.BindShortcuts(owner)
+	for _, value := range []string{"h", "hi", "hi!"} {
+		value := value
+		setText(&value)
+		Flush()
+	}
+
+	field := memory.GetElementByID("field")
+	if event := memory.DispatchKey(field, "keydown", "z", dom.Ctrl); !event.DefaultPrevented() {
+		t.Fatal("Ctrl+Z was not handled")
+	}
+	Flush()
+	if *text.Get() != "" {
+		t.Fatalf("grouped changes not undone together: %q", *text.Get())
+	}
+	memory.DispatchKey(field, "keydown", "Z", dom.Meta, dom.Shift)
+	Flush()
+	if *text.Get() != "hi!" {
+		t.Fatalf("after redo: %q", *text.Get())
+	}
+	if event := memory.DispatchKey(memory.Body(), "keydown", "z", dom.Ctrl); event.DefaultPrevented() {
+		t.Fatal("shortcut handled outside the component")
+	}
+}
@@ -0,0 +1,164 @@
+package html
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/vdom"
+	"github.com/google/uuid"
+)
+
+type attribute struct {
+	key, value string
+	// present is false for a boolean attribute that is off.
+	present bool
+}
+
+func (a attribute) build(b *builder) {
+	if !a.present {
+		return
+	}
+	for i, existing := range b.node.Attrs {
+		if existing.Key != a.key {
+			continue
+		}
+		if a.key == "class" && existing.Val != "" {
+			b.node.Attrs[i].Val += " " + a.value
+		} else {
+			b.node.Attrs[i].Val = a.value
+		}
+		return
+	}
+	b.node.Attrs = append(b.node.Attrs, vdom.Attribute{Key: a.key, Val: a.value})
+}
+
+// Attr sets the attribute key of the element it is passed to. The value is
+// escaped when rendered, and URL-valued attributes such as href, src and
+// srcset are sanitized with SafeURL. Setting an attribute twice keeps the
+// last value, except for class, whose values are joined.
+//
+// Event handler attributes (on*) panic: attach handlers with On or OnClick
+// and the like, which run Go code rather than a string of JavaScript. So do
+// keys with characters other than letters, digits, '_', ':', '.' and '-',
+// which could break out of the attribute when rendered.
+func Attr(key, value string) Node {
+	checkKey(key)
+	if sanitize, ok := urlAttributes[strings.ToLower(key)]; ok {
+		value = sanitize(value)
+	}
+	return attribute{key: key, value: value, present: true}
+}
+
+// Bool sets the boolean attribute key, such as disabled, when on is true.
+func Bool(key string, on bool) Node {
+	checkKey(key)
+	return attribute{key: key, present: on}
+}
+
+func checkKey(key string) {
+	if key == "" {
+		panic("html: empty attribute key")
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == ':' || r == '.' || r == '-') {
+			panic("html: attribute key " + strconv.Quote(key) + " has a character other than letters, digits, '_', ':', '.' and '-'")
+		}
+	}
+	if len(key) > 2 && strings.EqualFold(key[:2], "on") {
+		panic("html: attribute " + key + " is an inline script; use html.On(\"" + strings.ToLower(key[2:]) + "\", handler) instead")
+	}
+}
+
+// urlAttributes are the attributes whose value holds URLs that can run
+// script, with the function sanitizing each.
+var urlAttributes = map[string]func(string) string{
+	"href": SafeURL, "src": SafeURL, "action": SafeURL, "formaction": SafeURL,
+	"poster": SafeURL, "cite": SafeURL, "background": SafeURL, "xlink:href": SafeURL,
+	// data is the URL of an <object>
+	"data":   SafeURL,
+	"ping":   safeURLList,
+	"srcset": safeSrcset,
+}
+
+// safeURLList sanitizes a space-separated list of URLs, as in ping.
+func safeURLList(urls string) string {
+	fields := strings.Fields(urls)
+	for i, url := range fields {
+		fields[i] = SafeURL(url)
+	}
+	return strings.Join(fields, " ")
+}
+
+// safeSrcset sanitizes the URL of each image candidate in a srcset: a URL
+// followed by optional descriptors, with candidates separated by commas.
+func safeSrcset(srcset string) string {
+	var candidates []string
+	for _, candidate := range strings.Split(srcset, ",") {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		fields[0] = SafeURL(fields[0])
+		candidates = append(candidates, strings.Join(fields, " "))
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// unsafeURL replaces a URL SafeURL rejects. It leads nowhere when followed.
+const unsafeURL = "about:invalid#unsafe"
+
+// SafeURL returns url if it is relative or uses the http, https, mailto or
+// tel scheme, and a URL that does nothing otherwise, so links built from
+// user content cannot run script through javascript: or the like.
+func SafeURL(url string) string {
+	// Browsers ignore leading spaces and control characters, and tabs and
+	// newlines anywhere, when reading the scheme
+	normalized := strings.TrimLeftFunc(url, func(r rune) bool {
+		return r <= ' '
+	})
+	normalized = strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, normalized)
+	end := strings.IndexAny(normalized, ":/?#")
+	if end < 0 || normalized[end] != ':' {
+		return url
+	}
+	switch strings.ToLower(normalized[:end]) {
+	case "http", "https", "mailto", "tel":
+		return url
+	}
+	return unsafeURL
+}
+
+// ID sets the element's id to a component or element UUID.
+func ID(id uuid.UUID) Node { return Attr("id", id.String()) }
+
+// Key sets data-key, which identifies an element among its siblings when
+// the list is re-rendered.
+func Key(key string) Node { return Attr("data-key", key) }
+
+// Data sets the data-name attribute.
+func Data(name, value string) Node { return Attr("data-"+name, value) }
+
+// Aria sets the aria-name attribute.
+func Aria(name, value string) Node { return Attr("aria-"+name, value) }
+
+func Class(class string) Node             { return Attr("class", class) }
+func Style(style string) Node             { return Attr("style", style) }
+func Title(title string) Node             { return Attr("title", title) }
+func Role(role string) Node               { return Attr("role", role) }
+func Href(url string) Node                { return Attr("href", url) }
+func Src(url string) Node                 { return Attr("src", url) }
+func Alt(text string) Node                { return Attr("alt", text) }
+func Type(typ string) Node                { return Attr("type", typ) }
+func Name(name string) Node               { return Attr("name", name) }
+func Value(value string) Node             { return Attr("value", value) }
+func Placeholder(placeholder string) Node { return Attr("placeholder", placeholder) }
+func For(id string) Node                  { return Attr("for", id) }
+func Disabled(on bool) Node               { return Bool("disabled", on) }
+func Checked(on bool) Node                { return Bool("checked", on) }
+func Selected(on bool) Node               { return Bool("selected", on) }
+func Required(on bool) Node               { return Bool("required", on) }
@@ -0,0 +1,41 @@
+package html
+
+func A(content ...Node) *Element        { return El("a", content...) }
+func Article(content ...Node) *Element  { return El("article", content...) }
+func Aside(content ...Node) *Element    { return El("aside", content...) }
+func Br(content ...Node) *Element       { return El("br", content...) }
+func Button(content ...Node) *Element   { return El("button", content...) }
+func Code(content ...Node) *Element     { return El("code", content...) }
+func Div(content ...Node) *Element      { return El("div", content...) }
+func Em(content ...Node) *Element       { return El("em", content...) }
+func Footer(content ...Node) *Element   { return El("footer", content...) }
+func Form(content ...Node) *Element     { return El("form", content...) }
+func H1(content ...Node) *Element       { return El("h1", content...) }
+func H2(content ...Node) *Element       { return El("h2", content...) }
+func H3(content ...Node) *Element       { return El("h3", content...) }
+func H4(content ...Node) *Element       { return El("h4", content...) }
+func Header(content ...Node) *Element   { return El("header", content...) }
+func Hr(content ...Node) *Element       { return El("hr", content...) }
+func Img(content ...Node) *Element      { return El("img", content...) }
+func Input(content ...Node) *Element    { return El("input", content...) }
+func Label(content ...Node) *Element    { return El("label", content...) }
+func Li(content ...Node) *Element       { return El("li", content...) }
+func Main(content ...Node) *Element     { return El("main", content...) }
+func Nav(content ...Node) *Element      { return El("nav", content...) }
+func Ol(content ...Node) *Element       { return El("ol", content...) }
+func Option(content ...Node) *Element   { return El("option", content...) }
+func P(content ...Node) *Element        { return El("p", content...) }
+func Pre(content ...Node) *Element      { return El("pre", content...) }
+func Section(content ...Node) *Element  { return El("section", content...) }
+func Select(content ...Node) *Element   { return El("select", content...) }
+func Small(content ...Node) *Element    { return El("small", content...) }
+func Span(content ...Node) *Element     { return El("span", content...) }
+func Strong(content ...Node) *Element   { return El("strong", content...) }
+func Table(content ...Node) *Element    { return El("table", content...) }
+func Tbody(content ...Node) *Element    { return El("tbody", content...) }
+func Td(content ...Node) *Element       { return El("td", content...) }
+func Textarea(content ...Node) *Element { return El("textarea", content...) }
+func Th(content ...Node) *Element       { return El("th", content...) }
+func Thead(content ...Node) *Element    { return El("thead", content...) }
+func Tr(content ...Node) *Element       { return El("tr", content...) }
+func Ul(content ...Node) *Element       { return El("ul", content...) }
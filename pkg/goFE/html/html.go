@@ -0,0 +1,229 @@
+// Package html builds component markup from typed Go values instead of
+// templates:
+//
+//	func (c *Counter) Render() string {
+//		return c.events.Render(c.id,
+//			html.Div(html.ID(c.id), html.Class("counter"),
+//				html.Span(html.Text(c.label)),
+//				html.Button(html.OnClick(c.raise), html.Text("+")),
+//			),
+//		)
+//	}
+//
+//	func (c *Counter) InitEventListeners() {
+//		c.events.Bind()
+//	}
+//
+// Text and attribute values are escaped, URLs in href, src and the like are
+// sanitized and on* attributes are refused, so user content can be rendered
+// as is; Raw is the explicit opt-out for trusted markup. Event handlers are
+// attached inline: elements carrying one get an id derived from the
+// component's, stable across renders, so there is no need to allocate a
+// UUID per element.
+package html
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/vdom"
+	"github.com/google/uuid"
+)
+
+// Node is a piece of markup: an element, text, an attribute or an event
+// handler of the element it is passed to, or a group of those.
+type Node interface {
+	build(b *builder)
+}
+
+// builder accumulates the content of one element.
+type builder struct {
+	node     *vdom.Node
+	handlers []handler
+	// events assigns ids to elements with handlers; nil when rendering
+	// without Events.
+	events *Events
+}
+
+// Render renders nodes to HTML. Event handlers need a component to belong
+// to, so rendering one here panics; use Events.Render instead.
+func Render(nodes ...Node) string {
+	return VNodes(nodes...).InnerHTML()
+}
+
+// VNodes builds nodes into the children of a virtual div, the form a
+// component's output takes once parsed.
+func VNodes(nodes ...Node) *vdom.Node {
+	b := &builder{node: &vdom.Node{Type: vdom.ElementNode, Tag: "div"}}
+	for _, node := range nodes {
+		node.build(b)
+	}
+	return b.node
+}
+
+// Events collects the event handlers of a component's markup. Keep one in
+// the component, render with its Render method, and call Bind from
+// InitEventListeners. The zero value is ready to use.
+type Events struct {
+	lock     sync.Mutex
+	owner    uuid.UUID
+	next     int
+	bindings []binding
+}
+
+type binding struct {
+	id      uuid.UUID
+	event   string
+	handler func(event dom.Event)
+}
+
+// Render renders nodes to HTML for the component with the given id,
+// collecting their event handlers for Bind.
+func (e *Events) Render(componentID uuid.UUID, nodes ...Node) string {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.owner, e.next, e.bindings = componentID, 0, nil
+	b := &builder{node: &vdom.Node{Type: vdom.ElementNode, Tag: "div"}, events: e}
+	for _, node := range nodes {
+		node.build(b)
+	}
+	return b.node.InnerHTML()
+}
+
+// Bind registers the handlers collected by the last Render with the current
+// document. Call it from the component's InitEventListeners.
+func (e *Events) Bind() {
+	e.lock.Lock()
+	bindings := append([]binding{}, e.bindings...)
+	e.lock.Unlock()
+	document := goFE.GetDocument()
+	for _, binding := range bindings {
+		document.On(binding.id, binding.event, binding.handler)
+	}
+}
+
+// elementID returns the id of an element with handlers: its own, which must
+// then be set with ID, or else one derived from the component's id and the
+// element's position among those with handlers.
+func (e *Events) elementID(node *vdom.Node) uuid.UUID {
+	if id, ok := node.Attr("id"); ok {
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			panic("html: element #" + id + " has event handlers but its id is not a UUID; set it with html.ID")
+		}
+		return parsed
+	}
+	id := uuid.NewSHA1(e.owner, []byte("html/"+strconv.Itoa(e.next)))
+	e.next++
+	node.Attrs = append(node.Attrs, vdom.Attribute{Key: "id", Val: id.String()})
+	return id
+}
+
+// Element is an HTML element. Build one with a typed constructor such as Div,
+// or with El for any other tag.
+type Element struct {
+	tag     string
+	content []Node
+}
+
+// El returns the element with the given tag and content: child elements,
+// text, attributes and event handlers, in any order.
+func El(tag string, content ...Node) *Element {
+	return &Element{tag: tag, content: content}
+}
+
+func (el *Element) build(parent *builder) {
+	b := &builder{node: &vdom.Node{Type: vdom.ElementNode, Tag: el.tag}, events: parent.events}
+	for _, node := range el.content {
+		node.build(b)
+	}
+	if len(b.handlers) > 0 {
+		if b.events == nil {
+			panic("html: <" + el.tag + "> has event handlers; render it with Events.Render")
+		}
+		id := b.events.elementID(b.node)
+		for _, h := range b.handlers {
+			b.events.bindings = append(b.events.bindings, binding{id: id, event: h.event, handler: h.fn})
+		}
+	}
+	parent.node.AppendChild(b.node)
+}
+
+type text string
+
+func (t text) build(b *builder) {
+	b.node.AppendChild(&vdom.Node{Type: vdom.TextNode, Text: string(t)})
+}
+
+// Text returns a text node. Its content is escaped when rendered.
+func Text(content string) Node {
+	return text(content)
+}
+
+type raw string
+
+func (r raw) build(b *builder) {
+	vdom.ParseInto(b.node, string(r))
+}
+
+// Raw returns markup inserted as is, without escaping. Only use it for
+// trusted HTML, such as the output of other components' Render.
+func Raw(markup string) Node {
+	return raw(markup)
+}
+
+// Children renders the children of component in place, like
+// goFE.RenderChildren in a template.
+func Children(component goFE.Component) Node {
+	return raw(goFE.RenderChildren(component))
+}
+
+// Group is several nodes passed around as one.
+type Group []Node
+
+func (g Group) build(b *builder) {
+	for _, node := range g {
+		if node != nil {
+			node.build(b)
+		}
+	}
+}
+
+// If returns nodes when condition holds, and nothing otherwise.
+func If(condition bool, nodes ...Node) Node {
+	if !condition {
+		return Group(nil)
+	}
+	return Group(nodes)
+}
+
+// Map returns the nodes fn builds for each of items, in order.
+func Map[T any](items []T, fn func(item T) Node) Node {
+	group := make(Group, 0, len(items))
+	for _, item := range items {
+		group = append(group, fn(item))
+	}
+	return group
+}
+
+type handler struct {
+	event string
+	fn    func(event dom.Event)
+}
+
+func (h handler) build(b *builder) {
+	b.handlers = append(b.handlers, h)
+}
+
+// On handles event on the element it is passed to.
+func On(event string, fn func(event dom.Event)) Node {
+	return handler{event: event, fn: fn}
+}
+
+func OnClick(fn func(event dom.Event)) Node   { return On("click", fn) }
+func OnInput(fn func(event dom.Event)) Node   { return On("input", fn) }
+func OnChange(fn func(event dom.Event)) Node  { return On("change", fn) }
+func OnSubmit(fn func(event dom.Event)) Node  { return On("submit", fn) }
+func OnKeyDown(fn func(event dom.Event)) Node { return On("keydown", fn) }
@@ -0,0 +1,156 @@
+package html_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/html"
+	gofetest "github.com/cstevenson98/goFE/pkg/goFE/testing"
+	"github.com/google/uuid"
+)
+
+func TestRender_Escapes(t *testing.T) {
+	message := `<img src=x onerror="alert(1)">`
+	got := html.Render(
+		html.Div(html.Class("message"), html.Class("unread"), html.Title(`say "hi"`),
+			html.P(html.Text(message)),
+			html.Input(html.Type("checkbox"), html.Checked(true), html.Disabled(false)),
+			html.If(false, html.Span(html.Text("hidden"))),
+			html.Raw(`<b>trusted</b>`),
+		),
+	)
+	want := `<div class="message unread" title="say &quot;hi&quot;"><p>&lt;img src=x onerror="alert(1)"&gt;</p>` +
+		`<input type="checkbox" checked=""><b>trusted</b></div>`
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestRender_HandlerWithoutEvents(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("rendered a handler that could never be bound")
+		}
+	}()
+	html.Render(html.Button(html.OnClick(func(dom.Event) {})))
+}
+
+func TestSafeURL(t *testing.T) {
+	for url, want := range map[string]string{
+		"/cart?item=1":            "/cart?item=1",
+		"page#a:b":                "page#a:b",
+		"https://example.com/a:b": "https://example.com/a:b",
+		"mailto:ada@example.com":  "mailto:ada@example.com",
+		"javascript:alert(1)":     "about:invalid#unsafe",
+		" JavaScript:alert(1)":    "about:invalid#unsafe",
+		"java\tscript:alert(1)":   "about:invalid#unsafe",
+		"\x01javascript:alert(1)": "about:invalid#unsafe",
+		"data:text/html,<script>": "about:invalid#unsafe",
+		"vbscript:msgbox":         "about:invalid#unsafe",
+	} {
+		if got := html.SafeURL(url); got != want {
+			t.Errorf("SafeURL(%q) = %q, want %q", url, got, want)
+		}
+	}
+	got := html.Render(html.A(html.Href("javascript:alert(1)"), html.Attr("SRC", "javascript:x"), html.Text("x")))
+	if want := `<a href="about:invalid#unsafe" SRC="about:invalid#unsafe">x</a>`; got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+
+	for key, values := range map[string][2]string{
+		"srcset": {"a.png 1x, javascript:alert(1) 2x,b.png", "a.png 1x, about:invalid#unsafe 2x, b.png"},
+		"data":   {"javascript:alert(1)", "about:invalid#unsafe"},
+		"poster": {"javascript:alert(1)", "about:invalid#unsafe"},
+		"ping":   {"/track javascript:alert(1)", "/track about:invalid#unsafe"},
+	} {
+		got := html.Render(html.Div(html.Attr(key, values[0])))
+		if want := `<div ` + key + `="` + values[1] + `"></div>`; got != want {
+			t.Errorf("got  %s\nwant %s", got, want)
+		}
+	}
+}
+
+func TestAttr_RefusesInlineScript(t *testing.T) {
+	for _, key := range []string{"onclick", "OnError", "onmouseover"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Attr(%q) was accepted", key)
+				}
+			}()
+			html.Attr(key, "alert(1)")
+		}()
+	}
+}
+
+func TestAttr_RefusesInvalidKeys(t *testing.T) {
+	for _, key := range []string{"", `x" onclick="alert(1)`, "a b", "a>b", "a/b", "a=b", "é"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Attr(%q) was accepted", key)
+				}
+			}()
+			html.Attr(key, "x")
+		}()
+	}
+	got := html.Render(html.Div(html.Attr("xml:lang", "en"), html.Data("row_id", "1"), html.Attr("v-1.0", "x")))
+	if want := `<div xml:lang="en" data-row_id="1" v-1.0="x"></div>`; got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+}
+
+type todoList struct {
+	id     uuid.UUID
+	events html.Events
+	items  *goFE.State[[]string]
+	clicks int
+}
+
+func newTodoList() *todoList {
+	list := &todoList{id: uuid.New()}
+	list.items, _ = goFE.NewState[[]string](list, &[]string{"<write tests>"})
+	return list
+}
+
+func (l *todoList) Render() string {
+	return l.events.Render(l.id,
+		html.Ul(html.ID(l.id),
+			html.Map(*l.items.Value, func(item string) html.Node {
+				return html.Li(html.Text(item))
+			}),
+			html.Li(html.Button(html.OnClick(l.add), html.Text("Add "+strconv.Itoa(l.clicks)))),
+		),
+	)
+}
+
+func (l *todoList) add(dom.Event) {
+	l.clicks++
+	l.items.Update(func(prev *[]string) *[]string {
+		next := append(append([]string{}, *prev...), "item "+strconv.Itoa(l.clicks))
+		return &next
+	})
+}
+
+func (l *todoList) GetID() uuid.UUID              { return l.id }
+func (l *todoList) GetChildren() []goFE.Component { return nil }
+func (l *todoList) InitEventListeners()           { l.events.Bind() }
+
+func TestEvents_BindsInlineHandlers(t *testing.T) {
+	list := newTodoList()
+	h := gofetest.Mount(t, list)
+	if got := h.Query("li").TextContent(); got != "<write tests>" {
+		t.Fatalf("first item = %q", got)
+	}
+	id := h.Query("button").ID()
+	h.ClickElement(h.Query("button"))
+	h.ClickElement(h.Query("button"))
+	if items := h.QueryAll("li"); len(items) != 4 || items[2].TextContent() != "item 2" {
+		t.Fatalf("rendered %s", h.HTML())
+	}
+	if h.Query("button").ID() != id {
+		t.Fatal("the generated id changed between renders")
+	}
+}
@@ -1,11 +1,12 @@
 package goFE
 
-// Logger in the goFE package is a simple logger that uses println instead of fmt for
-// a small wasm binary size.
-// The level is hierarchical, with the highest level being the most verbose.
-type Logger struct {
-	Level int
-}
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
 
 const (
 	ERROR = iota
@@ -14,8 +15,197 @@ const (
 	DEBUG
 )
 
-func (l *Logger) Log(level int, message string) {
-	if l.Level >= level {
-		println(message)
+// LevelName returns the lowercase name of level, such as "warning".
+func LevelName(level int) string {
+	switch level {
+	case ERROR:
+		return "error"
+	case WARNING:
+		return "warning"
+	case INFO:
+		return "info"
+	case DEBUG:
+		return "debug"
+	}
+	return "level " + strconv.Itoa(level)
+}
+
+// Field is a key/value pair attached to a log record.
+type Field struct {
+	Key   string
+	Value string
+}
+
+// String returns a field with the given key and value.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int returns a field with the given key and integer value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: strconv.Itoa(value)}
+}
+
+// Err returns an "error" field holding err's message.
+func Err(err error) Field {
+	if err == nil {
+		return Field{Key: "error", Value: "<nil>"}
+	}
+	return Field{Key: "error", Value: err.Error()}
+}
+
+// ComponentID returns a "componentID" field.
+func ComponentID(id uuid.UUID) Field {
+	return Field{Key: "componentID", Value: id.String()}
+}
+
+// StateID returns a "stateID" field.
+func StateID(id uuid.UUID) Field {
+	return Field{Key: "stateID", Value: id.String()}
+}
+
+// Record is a single log entry, as handed to sinks.
+type Record struct {
+	Time    time.Time
+	Level   int
+	Logger  string
+	Message string
+	Fields  []Field
+}
+
+// Sink receives the records of a Logger. Write is called synchronously from
+// whatever logs, so sinks doing I/O should buffer.
+type Sink interface {
+	Write(record Record)
+}
+
+// Logger in the goFE package is a leveled, structured logger. The level is
+// hierarchical, with the highest level being the most verbose. Records go to
+// its sinks, or to the console when it has none; the console sink uses
+// println rather than fmt outside the browser, for a small binary.
+//
+// The framework logs through the logger passed to Init, under names such as
+// "state", "scheduler" and "document", with componentID and stateID fields.
+type Logger struct {
+	Level int
+	// Name prefixes the names of the loggers derived from this one.
+	Name string
+	// Sinks receive every record logged at Level or below; none means the
+	// console.
+	Sinks []Sink
+
+	// parent is the logger this one was derived from with Named or With,
+	// whose level and sinks it uses.
+	parent *Logger
+	fields []Field
+}
+
+// Named returns a logger for a part of the program: its records carry name,
+// appended to this logger's name with a dot. It shares this logger's level
+// and sinks.
+func (l *Logger) Named(name string) *Logger {
+	return &Logger{Name: joinLoggerNames(l.Name, name), parent: l.root(), fields: l.fields}
+}
+
+// With returns a logger adding fields to every record. It shares this
+// logger's name, level and sinks.
+func (l *Logger) With(fields ...Field) *Logger {
+	all := make([]Field, 0, len(l.fields)+len(fields))
+	all = append(append(all, l.fields...), fields...)
+	return &Logger{Name: l.Name, parent: l.root(), fields: all}
+}
+
+func (l *Logger) Log(level int, message string, fields ...Field) {
+	l.log("", level, message, fields...)
+}
+
+// Enabled reports whether records at level are logged.
+func (l *Logger) Enabled(level int) bool {
+	return l.root().Level >= level
+}
+
+// log logs under the logger named name below l, without deriving it.
+func (l *Logger) log(name string, level int, message string, fields ...Field) {
+	root := l.root()
+	if root.Level < level {
+		return
+	}
+	if len(l.fields) > 0 {
+		fields = append(append([]Field{}, l.fields...), fields...)
+	}
+	record := Record{
+		Time:    time.Now(),
+		Level:   level,
+		Logger:  joinLoggerNames(l.Name, name),
+		Message: message,
+		Fields:  fields,
+	}
+	if len(root.Sinks) == 0 {
+		consoleSink{}.Write(record)
+		return
+	}
+	for _, sink := range root.Sinks {
+		sink.Write(record)
+	}
+}
+
+func (l *Logger) root() *Logger {
+	if l.parent != nil {
+		return l.parent
+	}
+	return l
+}
+
+func joinLoggerNames(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	if name == "" {
+		return parent
+	}
+	return parent + "." + name
+}
+
+// NewConsoleSink returns a sink writing to the browser console, using the
+// console method matching each record's level and grouping a record's
+// fields under its message. Outside the browser it prints to standard error.
+func NewConsoleSink() Sink {
+	return consoleSink{}
+}
+
+// RingBuffer is a sink keeping the most recent records in memory, e.g. to
+// attach to a bug report or show in an overlay.
+type RingBuffer struct {
+	lock    sync.Mutex
+	records []Record
+	next    int
+	full    bool
+}
+
+// NewRingBuffer creates a ring buffer holding the last size records.
+func NewRingBuffer(size int) *RingBuffer {
+	if size <= 0 {
+		size = 1
+	}
+	return &RingBuffer{records: make([]Record, size)}
+}
+
+func (b *RingBuffer) Write(record Record) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.records[b.next] = record
+	b.next = (b.next + 1) % len(b.records)
+	if b.next == 0 {
+		b.full = true
+	}
+}
+
+// Records returns the records held, oldest first.
+func (b *RingBuffer) Records() []Record {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if !b.full {
+		return append([]Record{}, b.records[:b.next]...)
 	}
+	return append(append([]Record{}, b.records[b.next:]...), b.records[:b.next]...)
 }
@@ -0,0 +1,79 @@
+package goFE
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestLogger_NamedWithFields(t *testing.T) {
+	buffer := NewRingBuffer(2)
+	root := &Logger{Level: INFO, Name: "app", Sinks: []Sink{buffer}}
+	named := root.Named("cart").With(String("user", "ada"))
+	named.Log(DEBUG, "dropped")
+	named.Log(INFO, "first")
+	named.Log(WARNING, "second", Int("items", 3))
+	root.Level = DEBUG
+	named.Log(DEBUG, "third")
+
+	records := buffer.Records()
+	if len(records) != 2 || records[0].Message != "second" || records[1].Message != "third" {
+		t.Fatalf("ring buffer holds %+v", records)
+	}
+	if records[0].Logger != "app.cart" || len(records[0].Fields) != 2 ||
+		records[0].Fields[0] != String("user", "ada") || records[0].Fields[1] != Int("items", 3) {
+		t.Fatalf("record = %+v", records[0])
+	}
+}
+
+func TestLogger_FrameworkFields(t *testing.T) {
+	buffer := NewRingBuffer(10)
+	previous := logger
+	logger = &Logger{Level: DEBUG, Sinks: []Sink{buffer}}
+	defer func() { logger = previous }()
+
+	counter := newTestCounter()
+	records := buffer.Records()
+	if len(records) != 1 || records[0].Logger != "state" ||
+		records[0].Fields[0] != ComponentID(counter.id) || records[0].Fields[1] != StateID(counter.state.id) {
+		t.Fatalf("records = %+v", records)
+	}
+}
+
+func TestHTTPSink_Batches(t *testing.T) {
+	batches := make(chan []jsonRecord, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []jsonRecord
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil || r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		batches <- batch
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, &HTTPSinkOptions{
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		Headers:       map[string]string{"X-Token": "secret"},
+	})
+	log := &Logger{Level: DEBUG, Sinks: []Sink{sink}}
+	log.Log(INFO, "one", ComponentID(uuid.Nil))
+	log.Log(ERROR, "two")
+	batch := <-batches
+	if len(batch) != 2 || batch[0].Level != "info" || batch[0].Fields["componentID"] != uuid.Nil.String() || batch[1].Message != "two" {
+		t.Fatalf("batch = %+v", batch)
+	}
+
+	log.Log(WARNING, "three")
+	if err := sink.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if batch := <-batches; len(batch) != 1 || batch[0].Message != "three" {
+		t.Fatalf("flushed batch = %+v", batch)
+	}
+}
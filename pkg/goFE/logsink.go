@@ -0,0 +1,139 @@
+package goFE
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// HTTPSinkOptions configures NewHTTPSink.
+type HTTPSinkOptions struct {
+	// BatchSize is the number of records that triggers a send; 0 means 50.
+	BatchSize int
+	// FlushInterval bounds how long a record waits to be sent; 0 means 5s.
+	FlushInterval time.Duration
+	// Headers are added to each request, e.g. for authentication.
+	Headers map[string]string
+}
+
+// HTTPSink sends records to a backend endpoint in batches, as a JSON array
+// POSTed once BatchSize records are pending or FlushInterval has passed
+// since the oldest was written. A batch that fails to send is dropped.
+type HTTPSink struct {
+	endpoint string
+	options  HTTPSinkOptions
+
+	lock    sync.Mutex
+	pending []Record
+	timer   *time.Timer
+}
+
+// NewHTTPSink creates a sink POSTing records to endpoint.
+func NewHTTPSink(endpoint string, options *HTTPSinkOptions) *HTTPSink {
+	if options == nil {
+		options = &HTTPSinkOptions{}
+	}
+	s := &HTTPSink{endpoint: endpoint, options: *options}
+	if s.options.BatchSize <= 0 {
+		s.options.BatchSize = 50
+	}
+	if s.options.FlushInterval <= 0 {
+		s.options.FlushInterval = 5 * time.Second
+	}
+	return s
+}
+
+func (s *HTTPSink) Write(record Record) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.pending = append(s.pending, record)
+	if len(s.pending) >= s.options.BatchSize {
+		batch := s.take()
+		go s.send(batch)
+		return
+	}
+	if s.timer == nil {
+		s.timer = time.AfterFunc(s.options.FlushInterval, func() {
+			s.Flush()
+		})
+	}
+}
+
+// Flush sends the pending records straight away, e.g. before the page is
+// unloaded. In the browser it only starts the request and returns, so it can
+// be called from a JS callback such as beforeunload, and the error reports
+// failing to encode the records, not to deliver them.
+func (s *HTTPSink) Flush() error {
+	s.lock.Lock()
+	batch := s.take()
+	s.lock.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+	body, err := encodeRecords(batch)
+	if err != nil {
+		return err
+	}
+	return flushLogs(s.endpoint, s.options.Headers, body)
+}
+
+// take must be called with the lock held.
+func (s *HTTPSink) take() []Record {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	batch := s.pending
+	s.pending = nil
+	return batch
+}
+
+// jsonRecord is how a Record is sent by HTTPSink.
+type jsonRecord struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"`
+	Logger  string            `json:"logger,omitempty"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+}
+
+func (s *HTTPSink) send(batch []Record) error {
+	body, err := encodeRecords(batch)
+	if err != nil {
+		return err
+	}
+	return postLogs(s.endpoint, s.options.Headers, body)
+}
+
+// encodeRecords renders batch as the JSON array HTTPSink sends.
+func encodeRecords(batch []Record) ([]byte, error) {
+	records := make([]jsonRecord, len(batch))
+	for i, record := range batch {
+		records[i] = jsonRecord{
+			Time:    record.Time,
+			Level:   LevelName(record.Level),
+			Logger:  record.Logger,
+			Message: record.Message,
+		}
+		if len(record.Fields) > 0 {
+			records[i].Fields = make(map[string]string, len(record.Fields))
+			for _, field := range record.Fields {
+				records[i].Fields[field.Key] = field.Value
+			}
+		}
+	}
+	return json.Marshal(records)
+}
+
+// formatRecord renders record as a single line for plain-text output.
+func formatRecord(record Record) string {
+	line := LevelName(record.Level) + " "
+	if record.Logger != "" {
+		line += "[" + record.Logger + "] "
+	}
+	line += record.Message
+	for _, field := range record.Fields {
+		line += " " + field.Key + "=" + field.Value
+	}
+	return line
+}
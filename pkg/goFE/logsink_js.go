@@ -0,0 +1,88 @@
+//go:build js
+
+package goFE
+
+import (
+	"errors"
+	"strconv"
+	"syscall/js"
+)
+
+// consoleSink writes records to the browser console.
+type consoleSink struct{}
+
+func (consoleSink) Write(record Record) {
+	console := js.Global().Get("console")
+	method := "log"
+	switch record.Level {
+	case ERROR:
+		method = "error"
+	case WARNING:
+		method = "warn"
+	case INFO:
+		method = "info"
+	case DEBUG:
+		method = "debug"
+	}
+	header := record.Message
+	if record.Logger != "" {
+		header = "[" + record.Logger + "] " + header
+	}
+	if len(record.Fields) == 0 {
+		console.Call(method, header)
+		return
+	}
+	console.Call("groupCollapsed", header)
+	for _, field := range record.Fields {
+		console.Call(method, field.Key+": "+field.Value)
+	}
+	console.Call("groupEnd")
+}
+
+// postLogs sends body and waits for the response. It must not be called
+// from a JS callback, as the response is delivered by the event loop that
+// callback is holding up.
+func postLogs(endpoint string, headers map[string]string, body []byte) error {
+	done := make(chan error, 1)
+	var onFulfilled, onRejected js.Func
+	onFulfilled = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		if status := args[0].Get("status").Int(); status >= 300 {
+			done <- errors.New("sending logs: HTTP " + strconv.Itoa(status))
+		} else {
+			done <- nil
+		}
+		return nil
+	})
+	onRejected = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		done <- errors.New("sending logs: " + args[0].Call("toString").String())
+		return nil
+	})
+	defer onFulfilled.Release()
+	defer onRejected.Release()
+	fetchLogs(endpoint, headers, body).Call("then", onFulfilled, onRejected)
+	return <-done
+}
+
+// flushLogs starts sending body without waiting for the response, so it is
+// safe from JS callbacks such as beforeunload. The request is kept alive, so
+// it still arrives if the page unloads.
+func flushLogs(endpoint string, headers map[string]string, body []byte) error {
+	fetchLogs(endpoint, headers, body).Call("catch", js.Global().Get("Function").New(""))
+	return nil
+}
+
+// fetchLogs starts POSTing body to endpoint and returns the fetch promise.
+func fetchLogs(endpoint string, headers map[string]string, body []byte) js.Value {
+	jsHeaders := js.Global().Get("Object").New()
+	jsHeaders.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		jsHeaders.Set(key, value)
+	}
+	init := js.Global().Get("Object").New()
+	init.Set("method", "POST")
+	init.Set("headers", jsHeaders)
+	init.Set("body", string(body))
+	// Let the request outlive the page, so logs sent on unload arrive
+	init.Set("keepalive", true)
+	return js.Global().Call("fetch", endpoint, init)
+}
@@ -0,0 +1,42 @@
+//go:build !js
+
+package goFE
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// consoleSink prints records to standard error.
+type consoleSink struct{}
+
+func (consoleSink) Write(record Record) {
+	println(formatRecord(record))
+}
+
+func postLogs(endpoint string, headers map[string]string, body []byte) error {
+	request, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+	if response.StatusCode >= 300 {
+		return errors.New("sending logs: HTTP " + strconv.Itoa(response.StatusCode))
+	}
+	return nil
+}
+
+// flushLogs sends body and waits for the response, like postLogs.
+func flushLogs(endpoint string, headers map[string]string, body []byte) error {
+	return postLogs(endpoint, headers, body)
+}
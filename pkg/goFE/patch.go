@@ -0,0 +1,140 @@
+package goFE
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/vdom"
+)
+
+// applyPatches applies patches produced by vdom.Diff(old, ...) to element, the
+// live DOM node that old describes. It returns an error as soon as the live
+// DOM no longer matches the virtual tree, in which case the caller should
+// fall back to replacing the element wholesale.
+func applyPatches(doc dom.DOM, element dom.Node, old *vdom.Node, patches []vdom.Patch) error {
+	for _, patch := range patches {
+		target, vnode, err := resolvePath(element, old, patch.Path)
+		if err != nil {
+			return err
+		}
+		switch patch.Op {
+		case vdom.OpSetAttr:
+			setAttribute(target, patch.Key, patch.Value)
+		case vdom.OpRemoveAttr:
+			removeAttribute(target, patch.Key)
+		case vdom.OpSetText:
+			target.SetNodeValue(patch.Value)
+			if vnode.Parent != nil && vnode.Parent.Tag == "textarea" {
+				target.ParentNode().SetValue(patch.Value)
+			}
+		case vdom.OpReplace:
+			parent := target.ParentNode()
+			if parent == nil {
+				return fmt.Errorf("cannot replace a detached node")
+			}
+			parent.ReplaceChild(createNode(doc, patch.Node, vnode.Parent), target)
+		case vdom.OpInsert:
+			target.InsertBefore(createNode(doc, patch.Node, vnode), target.ChildAt(patch.Index))
+		case vdom.OpRemove:
+			child := target.ChildAt(patch.Index)
+			if child == nil {
+				return fmt.Errorf("no child %d to remove", patch.Index)
+			}
+			target.RemoveChild(child)
+		case vdom.OpMove:
+			child := target.ChildAt(patch.From)
+			if child == nil {
+				return fmt.Errorf("no child %d to move", patch.From)
+			}
+			target.InsertBefore(child, target.ChildAt(patch.Index))
+		}
+	}
+	return nil
+}
+
+// resolvePath walks path from element and old in lockstep, checking that the
+// live DOM still has the shape the virtual tree expects.
+func resolvePath(element dom.Node, old *vdom.Node, path []int) (dom.Node, *vdom.Node, error) {
+	target, vnode := element, old
+	for _, index := range path {
+		if index >= len(vnode.Children) {
+			return nil, nil, fmt.Errorf("virtual node has no child %d", index)
+		}
+		target, vnode = target.ChildAt(index), vnode.Children[index]
+		if target == nil {
+			return nil, nil, fmt.Errorf("DOM node has no child %d", index)
+		}
+		if !matchesNode(target, vnode) {
+			return nil, nil, fmt.Errorf("DOM node %s does not match virtual node %s", target.NodeName(), vnode.Tag)
+		}
+	}
+	return target, vnode, nil
+}
+
+func matchesNode(node dom.Node, vnode *vdom.Node) bool {
+	switch vnode.Type {
+	case vdom.TextNode:
+		return node.NodeType() == dom.TextNode
+	case vdom.CommentNode:
+		return node.NodeType() == dom.CommentNode
+	default:
+		return node.NodeType() == dom.ElementNode && strings.EqualFold(node.NodeName(), vnode.Tag)
+	}
+}
+
+// setAttribute updates an attribute and, for form controls, the matching
+// property, since browsers stop reflecting value/checked attributes once the
+// user has interacted with the control.
+func setAttribute(element dom.Node, key, value string) {
+	element.SetAttribute(key, value)
+	switch key {
+	case "value":
+		element.SetValue(value)
+	case "checked":
+		element.SetChecked(true)
+	case "selected":
+		element.SetSelected(true)
+	}
+}
+
+func removeAttribute(element dom.Node, key string) {
+	element.RemoveAttribute(key)
+	switch key {
+	case "checked":
+		element.SetChecked(false)
+	case "selected":
+		element.SetSelected(false)
+	}
+}
+
+// createNode builds a live DOM node for vnode. parent is the virtual node it
+// will be inserted under and determines the namespace for SVG content.
+func createNode(doc dom.DOM, vnode *vdom.Node, parent *vdom.Node) dom.Node {
+	switch vnode.Type {
+	case vdom.TextNode:
+		return doc.CreateTextNode(vnode.Text)
+	case vdom.CommentNode:
+		return doc.CreateComment(vnode.Text)
+	}
+	namespace := ""
+	if parent != nil {
+		namespace = parent.Namespace()
+	}
+	if strings.EqualFold(vnode.Tag, "svg") {
+		namespace = dom.SVGNamespace
+	}
+	var element dom.Node
+	if namespace != "" {
+		element = doc.CreateElementNS(namespace, vnode.Tag)
+	} else {
+		element = doc.CreateElement(vnode.Tag)
+	}
+	for _, attr := range vnode.Attrs {
+		element.SetAttribute(attr.Key, attr.Val)
+	}
+	for _, child := range vnode.Children {
+		element.AppendChild(createNode(doc, child, vnode))
+	}
+	return element
+}
@@ -0,0 +1,167 @@
+package goFE
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+)
+
+// Codec encodes persistent state values to the strings kept in storage.
+type Codec interface {
+	Encode(value any) (string, error)
+	Decode(data string, value any) error
+}
+
+// JSONCodec stores values as JSON, which is readable in the browser's
+// devtools but only keeps exported fields.
+var JSONCodec Codec = jsonCodec{}
+
+// GobCodec stores values as base64-encoded gob.
+var GobCodec Codec = gobCodec{}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Encode(value any) (string, error) {
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+func (jsonCodec) Decode(data string, value any) error {
+	return json.Unmarshal([]byte(data), value)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Encode(value any) (string, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(value); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+}
+
+func (gobCodec) Decode(data string, value any) error {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return err
+	}
+	return gob.NewDecoder(bytes.NewReader(raw)).Decode(value)
+}
+
+// PersistOptions configures NewPersistentState. The zero value keeps the
+// state in local storage as JSON, at version 0.
+type PersistOptions struct {
+	// Session keeps the state in session storage, which lasts as long as
+	// the tab, instead of local storage.
+	Session bool
+	// Codec encodes the value; nil means JSONCodec.
+	Codec Codec
+	// Version is the schema version written with the value. Bump it when the
+	// type changes in a way old data cannot be decoded into.
+	Version int
+	// Migrate converts data written at an older version into data for
+	// Version. Without it, or if it fails, older data is discarded and the
+	// initial value used.
+	Migrate func(version int, data string) (string, error)
+}
+
+// NewPersistentState creates a State like NewState whose value is kept in
+// browser storage under key. The stored value, if any, replaces initial;
+// every change is written back, and changes made by other tabs are picked up
+// through the storage event until component is torn down.
+func NewPersistentState[T any](component Component, key string, initial *T, options *PersistOptions) (*State[T], func(*T)) {
+	if options == nil {
+		options = &PersistOptions{}
+	}
+	codec := options.Codec
+	if codec == nil {
+		codec = JSONCodec
+	}
+	doc := currentDOM()
+	storage := doc.LocalStorage()
+	if options.Session {
+		storage = doc.SessionStorage()
+	}
+
+	load := func() (*T, bool) {
+		stored, ok := storage.GetItem(key)
+		if !ok {
+			return nil, false
+		}
+		value, err := decodeStored[T](stored, codec, options)
+		if err != nil {
+			logger.log("persistent", WARNING, "Discarding stored state", String("key", key), Err(err))
+			return nil, false
+		}
+		return value, true
+	}
+	if value, ok := load(); ok {
+		initial = value
+	}
+
+	state, setState := NewState[T](component, initial)
+	state.AddEffect(func(value *T) {
+		data, err := codec.Encode(value)
+		if err != nil {
+			logger.log("persistent", ERROR, "Encoding state", String("key", key), StateID(state.id), Err(err))
+			return
+		}
+		stored := strconv.Itoa(options.Version) + ":" + data
+		if current, _ := storage.GetItem(key); current != stored {
+			storage.SetItem(key, stored)
+		}
+	})
+	if !options.Session {
+		remove := doc.AddWindowListener("storage", func(event dom.Event) {
+			if event.Key() != key {
+				return
+			}
+			if value, ok := load(); ok {
+				setState(value)
+			}
+		})
+		onTeardown(component, remove)
+	}
+	return state, setState
+}
+
+// decodeStored decodes data written as "<version>:<encoded value>",
+// migrating it first if it was written at an older version.
+func decodeStored[T any](stored string, codec Codec, options *PersistOptions) (*T, error) {
+	prefix, data, ok := strings.Cut(stored, ":")
+	version, err := strconv.Atoi(prefix)
+	if !ok || err != nil {
+		return nil, errors.New("missing version")
+	}
+	if version > options.Version {
+		return nil, errors.New("written by a newer version " + prefix)
+	}
+	if version < options.Version {
+		if options.Migrate == nil {
+			return nil, errors.New("no migration from version " + prefix)
+		}
+		if data, err = options.Migrate(version, data); err != nil {
+			return nil, err
+		}
+	}
+	var value *T
+	if err := codec.Decode(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// currentDOM returns the DOM of the current document, or the default one for
+// the build before a document is set.
+func currentDOM() dom.DOM {
+	if d := GetDocument(); d != nil {
+		return d.dom
+	}
+	return defaultDOM()
+}
@@ -0,0 +1,79 @@
+package goFE
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+type draft struct {
+	Title string
+	Body  string
+}
+
+func TestPersistentState_LoadsSavesAndSyncs(t *testing.T) {
+	memory := dom.NewMemory()
+	SetDocument(NewDocumentWithDOM(nil, memory))
+	defer SetDocument(nil)
+	memory.LocalStorage().SetItem("draft", `1:"Hello"`)
+
+	owner := &selectProbe{id: uuid.New()}
+	options := &PersistOptions{
+		Version: 2,
+		Migrate: func(version int, data string) (string, error) {
+			return `{"Title":` + data + `}`, nil
+		},
+	}
+	state, setState := NewPersistentState(owner, "draft", &draft{}, options)
+	if state.Value.Title != "Hello" {
+		t.Fatalf("migrated value = %+v", *state.Value)
+	}
+
+	setState(&draft{Title: "Hello", Body: "world"})
+	Flush()
+	if stored, _ := memory.LocalStorage().GetItem("draft"); stored != `2:{"Title":"Hello","Body":"world"}` {
+		t.Fatalf("stored %q", stored)
+	}
+
+	memory.SetItemFromAnotherTab("draft", `2:{"Title":"Elsewhere"}`)
+	Flush()
+	if state.Value.Title != "Elsewhere" {
+		t.Fatalf("value after another tab wrote = %+v", *state.Value)
+	}
+
+	killAllStates(owner)
+	memory.SetItemFromAnotherTab("draft", `2:{"Title":"Ignored"}`)
+	Flush()
+	if state.Value.Title != "Elsewhere" {
+		t.Fatal("torn down state followed another tab")
+	}
+}
+
+func TestPersistentState_GobAndDiscard(t *testing.T) {
+	memory := dom.NewMemory()
+	SetDocument(NewDocumentWithDOM(nil, memory))
+	defer SetDocument(nil)
+	memory.SessionStorage().SetItem("bad", "3:{}")
+
+	owner := &selectProbe{id: uuid.New()}
+	defer killAllStates(owner)
+	options := &PersistOptions{Session: true, Codec: GobCodec}
+	bad, _ := NewPersistentState(owner, "bad", &draft{Title: "initial"}, options)
+	if bad.Value.Title != "initial" {
+		t.Fatalf("data from a newer version was not discarded: %+v", *bad.Value)
+	}
+
+	_, setState := NewPersistentState(owner, "gob", &draft{}, options)
+	setState(&draft{Title: "binary"})
+	Flush()
+	stored, _ := memory.SessionStorage().GetItem("gob")
+	if !strings.HasPrefix(stored, "0:") {
+		t.Fatalf("stored %q", stored)
+	}
+	restored, _ := NewPersistentState(owner, "gob", &draft{}, options)
+	if restored.Value.Title != "binary" {
+		t.Fatalf("gob round trip = %+v", *restored.Value)
+	}
+}
@@ -0,0 +1,97 @@
+package goFE
+
+import (
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/cstevenson98/goFE/pkg/goFE/vdom"
+	"github.com/google/uuid"
+)
+
+// Portal renders its children into another element of the page, such as a
+// #modal-root next to #root, so modals, toasts and dropdowns are not clipped
+// by their parent's layout. Where it appears in its parent's markup it only
+// leaves an empty placeholder, and re-rendering the parent leaves the
+// children alone.
+//
+// The children otherwise behave as if they were rendered in place: they
+// belong to the parent's component tree, so they keep their state, get
+// lifecycle hooks and are torn down with it, and events bubble from them to
+// the parent's handlers. The target element must be outside the element
+// the document is mounted in.
+type Portal struct {
+	id        uuid.UUID
+	contentID uuid.UUID
+	targetID  string
+	children  []Component
+
+	// container holds the children inside the target once mounted.
+	container dom.Node
+}
+
+// NewPortal creates a portal rendering children into the element with the
+// id targetID.
+func NewPortal(targetID string, children ...Component) *Portal {
+	return &Portal{
+		id:        uuid.New(),
+		contentID: uuid.New(),
+		targetID:  targetID,
+		children:  children,
+	}
+}
+
+func (p *Portal) Render() string {
+	return `<template id="` + p.id.String() + `"></template>`
+}
+
+func (p *Portal) GetID() uuid.UUID {
+	return p.id
+}
+
+func (p *Portal) GetChildren() []Component {
+	return p.children
+}
+
+func (p *Portal) InitEventListeners() {}
+
+// mountPortals renders the content of every portal in components that is not
+// mounted yet into its target. It runs after components are rendered and
+// before their listeners and lifecycle hooks, so portal content is in the
+// DOM by then.
+func (d *Document) mountPortals(components []Component) {
+	for _, component := range components {
+		if portal, ok := component.(*Portal); ok && portal.container == nil {
+			d.mountPortal(portal)
+		}
+		d.mountPortals(component.GetChildren())
+	}
+}
+
+func (d *Document) mountPortal(p *Portal) {
+	target := d.dom.GetElementByID(p.targetID)
+	if target == nil {
+		d.log(ERROR, "No target element for portal", String("targetID", p.targetID), ComponentID(p.id))
+		return
+	}
+	contentID := p.contentID.String()
+	content := RenderChildren(p)
+	p.container = d.dom.CreateElement("div")
+	p.container.SetAttribute("id", contentID)
+	p.container.SetInnerHTML(content)
+	target.AppendChild(p.container)
+
+	wrapper := &vdom.Node{Type: vdom.ElementNode, Tag: "div", Attrs: []vdom.Attribute{{Key: "id", Val: contentID}}}
+	vdom.ParseInto(wrapper, content)
+	d.indexVNodes(wrapper)
+	placeholderID := p.id.String()
+	d.events.addPortal(p.container, func() dom.Node {
+		return d.dom.GetElementByID(placeholderID)
+	})
+
+	onTeardown(p, func() {
+		d.events.removePortal(contentID)
+		d.unindexVNodes(wrapper)
+		if parent := p.container.ParentNode(); parent != nil {
+			parent.RemoveChild(p.container)
+		}
+		p.container = nil
+	})
+}
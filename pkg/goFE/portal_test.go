@@ -0,0 +1,66 @@
+package goFE
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+type modalHost struct {
+	id       uuid.UUID
+	portal   *Portal
+	clicks   int
+	state    *State[int]
+	setState func(*int)
+}
+
+func (h *modalHost) Render() string {
+	return `<section id="` + h.id.String() + `">` + strconv.Itoa(*h.state.Value) + RenderChildren(h) + `</section>`
+}
+func (h *modalHost) GetID() uuid.UUID         { return h.id }
+func (h *modalHost) GetChildren() []Component { return []Component{h.portal} }
+func (h *modalHost) InitEventListeners() {
+	document.On(h.id, "click", func(dom.Event) { h.clicks++ })
+}
+
+func TestPortal_RendersOutsideRoot(t *testing.T) {
+	memory := dom.NewMemory()
+	modalRoot := memory.CreateElement("div")
+	modalRoot.SetAttribute("id", "modal-root")
+	memory.Body().AppendChild(modalRoot)
+
+	var log []string
+	counter := newTestCounter()
+	probe := newLifecycleProbe("modal", &log, counter)
+	host := &modalHost{id: uuid.New(), portal: NewPortal("modal-root", probe)}
+	host.state, host.setState = NewState[int](host, new(int))
+	SetDocument(NewDocumentWithDOM([]Component{host}, memory))
+	defer SetDocument(nil)
+	document.Init()
+
+	button := memory.GetElementByID(counter.buttonID.String())
+	if button == nil || modalRoot.ChildCount() != 1 || len(log) != 1 || log[0] != "mount modal" {
+		t.Fatalf("portal content not mounted in #modal-root: %s, %v", modalRoot.InnerHTML(), log)
+	}
+
+	one := 1
+	host.setState(&one)
+	memory.Dispatch(button, "click")
+	Flush()
+	if !memory.GetElementByID(counter.buttonID.String()).IsSameNode(button) {
+		t.Fatal("re-rendering the parent replaced the portal content")
+	}
+	if got := memory.GetElementByID(counter.id.String()).ChildAt(1).TextContent(); got != "1" {
+		t.Fatalf("portal child rendered %q after its state changed", got)
+	}
+	if host.clicks != 1 {
+		t.Fatalf("click reached the logical parent %d times", host.clicks)
+	}
+
+	killAllStates(host)
+	if modalRoot.ChildCount() != 0 || log[len(log)-1] != "unmount modal" {
+		t.Fatalf("portal content left behind: %s, %v", modalRoot.InnerHTML(), log)
+	}
+}
@@ -0,0 +1,349 @@
+package goFE
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+// Kinds of SessionEntry.
+const (
+	EntryState = "state"
+	EntryEvent = "event"
+)
+
+// ErrSessionBounds is returned when stepping past either end of a session.
+var ErrSessionBounds = errors.New("no more entries in the session")
+
+// SessionEntry is a state change or DOM event in a recorded session. The
+// component involved is identified by its path in the tree (as for
+// RenderToString), since component and state ids differ between page loads.
+type SessionEntry struct {
+	Kind string `json:"kind"`
+	// Time is when the entry happened, since the recording started.
+	Time time.Duration `json:"t"`
+	// Path and Type locate the component that owns the state, or the
+	// handler of the event.
+	Path        string `json:"path"`
+	Type        string `json:"type"`
+	ComponentID string `json:"componentID"`
+
+	// State is the index of the state among its component's states, in
+	// creation order, and StateID its id in the recorded session. Value is
+	// the new value as JSON and Previous the value it replaced.
+	State    int             `json:"state,omitempty"`
+	StateID  string          `json:"stateID,omitempty"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Previous json.RawMessage `json:"previous,omitempty"`
+
+	// Event is the DOM event type, with the id of the element it was
+	// dispatched to and, where relevant, its key and the target's value.
+	Event       string `json:"event,omitempty"`
+	Target      string `json:"target,omitempty"`
+	Key         string `json:"key,omitempty"`
+	TargetValue string `json:"targetValue,omitempty"`
+}
+
+// Session is a recorded sequence of state changes and DOM events.
+type Session struct {
+	Started time.Time      `json:"started"`
+	Entries []SessionEntry `json:"entries"`
+}
+
+// ParseSession decodes a session saved with Recorder.JSON or Download.
+func ParseSession(data []byte) (*Session, error) {
+	var session Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+var recordingLock sync.Mutex
+
+// recording is the active Recorder, if any.
+var recording *Recorder
+
+// Recorder records every state change and handled DOM event of the mounted
+// documents, to reproduce a session elsewhere with a Replayer. State values
+// are recorded as JSON, so they must round-trip through encoding/json.
+type Recorder struct {
+	lock    sync.Mutex
+	session Session
+}
+
+// StartRecording starts a new recording, replacing the active one if any.
+func StartRecording() *Recorder {
+	r := &Recorder{session: Session{Started: time.Now(), Entries: []SessionEntry{}}}
+	recordingLock.Lock()
+	recording = r
+	recordingLock.Unlock()
+	return r
+}
+
+// Stop stops recording; the session recorded so far is kept.
+func (r *Recorder) Stop() {
+	recordingLock.Lock()
+	defer recordingLock.Unlock()
+	if recording == r {
+		recording = nil
+	}
+}
+
+// Session returns a copy of the session recorded so far.
+func (r *Recorder) Session() *Session {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return &Session{Started: r.session.Started, Entries: append([]SessionEntry{}, r.session.Entries...)}
+}
+
+// JSON encodes the session recorded so far, for ParseSession.
+func (r *Recorder) JSON() ([]byte, error) {
+	return json.Marshal(r.Session())
+}
+
+// Download saves the session recorded so far as a JSON file named filename,
+// through the browser's download prompt. It fails outside the browser.
+func (r *Recorder) Download(filename string) error {
+	data, err := r.JSON()
+	if err != nil {
+		return err
+	}
+	return downloadFile(filename, "application/json", data)
+}
+
+func (r *Recorder) add(entry SessionEntry) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	entry.Time = time.Since(r.session.Started)
+	r.session.Entries = append(r.session.Entries, entry)
+}
+
+// activeRecorder returns the active Recorder, or nil if there is none or a
+// Replayer is re-applying a change, which is not recorded again.
+func activeRecorder() *Recorder {
+	if sched.isReplaying() {
+		return nil
+	}
+	recordingLock.Lock()
+	defer recordingLock.Unlock()
+	return recording
+}
+
+// recordStateChange records a state of owner changing to value from
+// previous, its JSON encoding. It runs on the scheduler.
+func recordStateChange(r *Recorder, owner Component, state stateHandle, id uuid.UUID, previous json.RawMessage, value interface{}) {
+	entry := SessionEntry{
+		Kind:        EntryState,
+		Path:        pathOf(owner.GetID()),
+		Type:        reflect.TypeOf(owner).String(),
+		ComponentID: owner.GetID().String(),
+		State:       -1,
+		StateID:     id.String(),
+	}
+	registryLock.Lock()
+	for i, handle := range componentStates[owner.GetID()] {
+		if handle == state {
+			entry.State = i
+		}
+	}
+	registryLock.Unlock()
+	var err error
+	if entry.Value, err = json.Marshal(value); err != nil {
+		logger.log("recording", WARNING, "Could not record state change", ComponentID(owner.GetID()), StateID(id), Err(err))
+		return
+	}
+	entry.Previous = previous
+	r.add(entry)
+}
+
+// recordEvent records event e being handled by a handler of owner.
+func recordEvent(owner uuid.UUID, event string, e dom.Event) {
+	r := activeRecorder()
+	if r == nil {
+		return
+	}
+	entry := SessionEntry{
+		Kind:        EntryEvent,
+		Path:        pathOf(owner),
+		ComponentID: owner.String(),
+		Event:       event,
+		Key:         e.Key(),
+	}
+	if component := componentAt(entry.Path); component != nil {
+		entry.Type = reflect.TypeOf(component).String()
+	}
+	if target := e.Target(); target != nil {
+		entry.Target = target.ID()
+		entry.TargetValue = target.Value()
+	}
+	r.add(entry)
+}
+
+// pathOf returns the tree path of the component with the given id, or "" if
+// it is not mounted.
+func pathOf(id uuid.UUID) string {
+	found := errors.New("found")
+	var path string
+	walkTree(treeRoots(), "", func(p string, component Component) error {
+		if component.GetID() == id {
+			path = p
+			return found
+		}
+		return nil
+	})
+	return path
+}
+
+// componentAt returns the component at the given tree path, or nil.
+func componentAt(path string) Component {
+	found := errors.New("found")
+	var at Component
+	walkTree(treeRoots(), "", func(p string, component Component) error {
+		if p == path {
+			at = component
+			return found
+		}
+		return nil
+	})
+	return at
+}
+
+// Replayer re-applies a recorded session to the mounted documents, which
+// must have been built from the same component tree as the recorded page.
+// It can step through the session in either direction.
+//
+// Only state changes are re-applied; DOM events are kept as markers of what
+// the user did, their effects being the state changes recorded after them.
+// Handlers and loaders are not re-run, and neither are the effects of the
+// states changed (nor of the Computed values depending on them), as the
+// changes those made are in the session too; this keeps the replay
+// deterministic. An active Recorder does not record the replayed changes.
+type Replayer struct {
+	session  *Session
+	position int
+}
+
+// NewReplayer prepares session for replay, starting before its first entry.
+func NewReplayer(session *Session) *Replayer {
+	return &Replayer{session: session}
+}
+
+// Len returns the number of entries in the session.
+func (p *Replayer) Len() int {
+	return len(p.session.Entries)
+}
+
+// Position returns the number of entries applied so far.
+func (p *Replayer) Position() int {
+	return p.position
+}
+
+// Entry returns entry i of the session.
+func (p *Replayer) Entry(i int) SessionEntry {
+	return p.session.Entries[i]
+}
+
+// Step applies the next entry and renders the result.
+func (p *Replayer) Step() error {
+	if p.position >= len(p.session.Entries) {
+		return ErrSessionBounds
+	}
+	entry := p.session.Entries[p.position]
+	if entry.Kind == EntryState {
+		if err := applyEntry(entry, entry.Value); err != nil {
+			return err
+		}
+	}
+	p.position++
+	return nil
+}
+
+// StepBack undoes the last entry applied, restoring the value the state had
+// before it, and renders the result. Components that were torn down and
+// created again in between start from their initial state.
+func (p *Replayer) StepBack() error {
+	if p.position == 0 {
+		return ErrSessionBounds
+	}
+	entry := p.session.Entries[p.position-1]
+	if entry.Kind == EntryState {
+		if err := applyEntry(entry, entry.Previous); err != nil {
+			return err
+		}
+	}
+	p.position--
+	return nil
+}
+
+// Seek steps forwards or backwards until position entries are applied.
+func (p *Replayer) Seek(position int) error {
+	if position < 0 || position > len(p.session.Entries) {
+		return ErrSessionBounds
+	}
+	for p.position < position {
+		if err := p.Step(); err != nil {
+			return err
+		}
+	}
+	for p.position > position {
+		if err := p.StepBack(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Play steps through the rest of the session, waiting between entries as
+// long as the user did divided by speed (so 2 plays twice as fast, and 0
+// does not wait). It stops early when ctx is done.
+func (p *Replayer) Play(ctx context.Context, speed float64) error {
+	for p.position < len(p.session.Entries) {
+		if speed > 0 && p.position > 0 {
+			wait := p.session.Entries[p.position].Time - p.session.Entries[p.position-1].Time
+			select {
+			case <-time.After(time.Duration(float64(wait) / speed)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := p.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEntry sets the state an entry refers to to value, then flushes.
+func applyEntry(entry SessionEntry, value json.RawMessage) error {
+	component := componentAt(entry.Path)
+	if component == nil {
+		return fmt.Errorf("no component at %s for the recorded %s", entry.Path, entry.Type)
+	}
+	if typ := reflect.TypeOf(component).String(); typ != entry.Type {
+		return fmt.Errorf("component at %s is a %s, recorded a %s", entry.Path, typ, entry.Type)
+	}
+	registryLock.Lock()
+	states := componentStates[component.GetID()]
+	registryLock.Unlock()
+	if entry.State < 0 || entry.State >= len(states) {
+		return fmt.Errorf("component at %s has no state %d", entry.Path, entry.State)
+	}
+	if value == nil {
+		value = json.RawMessage("null")
+	}
+	if err := states[entry.State].replay(value); err != nil {
+		return fmt.Errorf("replaying state %d of component %s: %w", entry.State, entry.Path, err)
+	}
+	Flush()
+	return nil
+}
@@ -0,0 +1,34 @@
+//go:build js
+
+package goFE
+
+import "syscall/js"
+
+// downloadFile offers data to the user as a file, by clicking a temporary
+// link to a Blob URL.
+func downloadFile(filename, mimeType string, data []byte) error {
+	array := js.Global().Get("Uint8Array").New(len(data))
+	js.CopyBytesToJS(array, data)
+	options := js.Global().Get("Object").New()
+	options.Set("type", mimeType)
+	blob := js.Global().Get("Blob").New([]interface{}{array}, options)
+	url := js.Global().Get("URL").Call("createObjectURL", blob)
+
+	link := js.Global().Get("document").Call("createElement", "a")
+	link.Set("href", url)
+	link.Set("download", filename)
+	body := js.Global().Get("document").Get("body")
+	body.Call("appendChild", link)
+	link.Call("click")
+	body.Call("removeChild", link)
+
+	// Revoked once the browser has picked the download up
+	var revoke js.Func
+	revoke = js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		js.Global().Get("URL").Call("revokeObjectURL", url)
+		revoke.Release()
+		return nil
+	})
+	js.Global().Call("setTimeout", revoke, 0)
+	return nil
+}
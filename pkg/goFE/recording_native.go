@@ -0,0 +1,9 @@
+//go:build !js
+
+package goFE
+
+import "errors"
+
+func downloadFile(filename, mimeType string, data []byte) error {
+	return errors.New("downloading " + filename + " needs a browser")
+}
@@ -0,0 +1,111 @@
+package goFE
+
+import (
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+)
+
+func TestRecording_ReplayInFreshDocument(t *testing.T) {
+	memory := dom.NewMemory()
+	counter := newTestCounter()
+	SetDocument(NewDocumentWithDOM([]Component{newLifecycleProbe("parent", new([]string), counter)}, memory))
+	document.Init()
+	recorder := StartRecording()
+	for i := 0; i < 2; i++ {
+		memory.Dispatch(memory.GetElementByID(counter.buttonID.String()), "click")
+		Flush()
+	}
+	recorder.Stop()
+	memory.Dispatch(memory.GetElementByID(counter.buttonID.String()), "click")
+	Flush()
+	data, err := recorder.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	killAllStates(document.GetComponentTree()[0])
+
+	session, err := ParseSession(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(session.Entries) != 4 || session.Entries[0].Kind != EntryEvent || session.Entries[0].Event != "click" ||
+		session.Entries[1].Kind != EntryState || session.Entries[1].Path != "0.0" || string(session.Entries[1].Value) != "1" {
+		t.Fatalf("session = %+v", session.Entries)
+	}
+
+	// Replay into a new page load, where every id is different
+	memory = dom.NewMemory()
+	replayed := newTestCounter()
+	SetDocument(NewDocumentWithDOM([]Component{newLifecycleProbe("parent", new([]string), replayed)}, memory))
+	defer SetDocument(nil)
+	document.Init()
+	count := func() string {
+		return memory.GetElementByID(replayed.id.String()).ChildAt(1).TextContent()
+	}
+	replayer := NewReplayer(session)
+	if err := replayer.Seek(replayer.Len()); err != nil || count() != "2" {
+		t.Fatalf("after replaying, count = %s, err = %v", count(), err)
+	}
+	if err := replayer.StepBack(); err != nil || count() != "1" {
+		t.Fatalf("after stepping back, count = %s, err = %v", count(), err)
+	}
+	if err := replayer.Seek(0); err != nil || count() != "0" {
+		t.Fatalf("after seeking to the start, count = %s, err = %v", count(), err)
+	}
+	if err := replayer.StepBack(); err != ErrSessionBounds {
+		t.Fatalf("stepping back from the start: %v", err)
+	}
+	killAllStates(document.GetComponentTree()[0])
+
+	// A differently shaped tree is reported rather than patched blindly
+	SetDocument(NewDocumentWithDOM([]Component{newLifecycleProbe("parent", new([]string), newLifecycleProbe("child", new([]string)))}, dom.NewMemory()))
+	document.Init()
+	if err := NewReplayer(session).Seek(2); err == nil {
+		t.Fatal("replayed a session into a different tree")
+	}
+	killAllStates(document.GetComponentTree()[0])
+}
+
+func TestReplayer_SkipsEffectsAndRecording(t *testing.T) {
+	memory := dom.NewMemory()
+	counter := newTestCounter()
+	SetDocument(NewDocumentWithDOM([]Component{counter}, memory))
+	defer SetDocument(nil)
+	document.Init()
+	defer killAllStates(counter)
+	effects := 0
+	counter.state.AddEffect(func(*int) { effects++ })
+
+	recorder := StartRecording()
+	memory.Dispatch(memory.GetElementByID(counter.buttonID.String()), "click")
+	Flush()
+	recorder.Stop()
+	session := recorder.Session()
+	zero := 0
+	counter.setState(&zero)
+	Flush()
+
+	effects = 0
+	again := StartRecording()
+	defer again.Stop()
+	if err := NewReplayer(session).Seek(len(session.Entries)); err != nil {
+		t.Fatal(err)
+	}
+	if *counter.state.Get() != 1 {
+		t.Fatalf("count = %d after replaying, want 1", *counter.state.Get())
+	}
+	if effects != 0 {
+		t.Fatalf("the replay ran %d effects", effects)
+	}
+	if entries := again.Session().Entries; len(entries) != 0 {
+		t.Fatalf("the replay was recorded: %+v", entries)
+	}
+
+	// Effects run again once the replay is over
+	counter.setState(&zero)
+	Flush()
+	if effects != 1 {
+		t.Fatalf("effects = %d after a normal update, want 1", effects)
+	}
+}
@@ -0,0 +1,241 @@
+package goFE
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Loader fetches the data of a Resource. It should give up when ctx is
+// cancelled, which happens when the resource is refetched or its component
+// torn down.
+type Loader[T any] func(ctx context.Context) (*T, error)
+
+// ResourceOptions configures NewResource.
+type ResourceOptions struct {
+	// Timeout bounds each load; 0 means no limit beyond the component's
+	// lifetime.
+	Timeout time.Duration
+	// Key shares loaded data between resources: a new resource with the key
+	// of one loaded before starts out with that (possibly stale) data and
+	// revalidates it in the background.
+	Key string
+	// CacheTime is how long the data loaded for Key stays cached after it
+	// was loaded; 0 means 5 minutes.
+	CacheTime time.Duration
+}
+
+// defaultResourceCacheTime is the CacheTime used when it is not set.
+const defaultResourceCacheTime = 5 * time.Minute
+
+// cachedResource is data loaded for a ResourceOptions.Key.
+type cachedResource struct {
+	data    interface{}
+	expires time.Time
+}
+
+var resourceCacheLock sync.Mutex
+
+// resourceCache holds the last data loaded for each ResourceOptions.Key,
+// until it expires.
+var resourceCache = make(map[string]cachedResource)
+
+// cachedData returns the unexpired data cached for key, if any.
+func cachedData(key string) (interface{}, bool) {
+	resourceCacheLock.Lock()
+	defer resourceCacheLock.Unlock()
+	entry, ok := resourceCache[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(resourceCache, key)
+		return nil, false
+	}
+	return entry.data, true
+}
+
+// cacheData caches data for key for cacheTime, evicting the expired entries.
+func cacheData(key string, data interface{}, cacheTime time.Duration) {
+	if cacheTime <= 0 {
+		cacheTime = defaultResourceCacheTime
+	}
+	now := time.Now()
+	resourceCacheLock.Lock()
+	defer resourceCacheLock.Unlock()
+	for cachedKey, entry := range resourceCache {
+		if now.After(entry.expires) {
+			delete(resourceCache, cachedKey)
+		}
+	}
+	resourceCache[key] = cachedResource{data: data, expires: now.Add(cacheTime)}
+}
+
+// resetResourceCache empties the cache.
+func resetResourceCache() {
+	resourceCacheLock.Lock()
+	defer resourceCacheLock.Unlock()
+	resourceCache = make(map[string]cachedResource)
+}
+
+// Resource is data loaded asynchronously on behalf of a component. The load
+// starts when the resource is created, and the component is re-rendered as
+// it progresses; render from Loading, Err and Data. Data read inside a
+// Computed is tracked like a State.
+type Resource[T any] struct {
+	id      uuid.UUID
+	owner   Component
+	loader  Loader[T]
+	options ResourceOptions
+	// ctx lives as long as the owner and is the parent of every load.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock    sync.Mutex
+	data    *T
+	err     error
+	loading bool
+	// cancelLoad aborts the load in flight, if any.
+	cancelLoad context.CancelFunc
+	generation int
+	dependents map[uuid.UUID]func()
+}
+
+// NewResource starts loading data for component with loader. Loads are
+// cancelled when component is torn down.
+func NewResource[T any](component Component, loader Loader[T], options *ResourceOptions) *Resource[T] {
+	if options == nil {
+		options = &ResourceOptions{}
+	}
+	r := &Resource[T]{
+		id:         uuid.New(),
+		owner:      component,
+		loader:     loader,
+		options:    *options,
+		dependents: make(map[uuid.UUID]func()),
+	}
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	if options.Key != "" {
+		if cached, ok := cachedData(options.Key); ok {
+			r.data, _ = cached.(*T)
+		}
+	}
+	onTeardown(component, r.cancel)
+	r.Refetch()
+	return r
+}
+
+// Loading reports whether a load is in flight. Data may still hold the
+// previous result meanwhile.
+func (r *Resource[T]) Loading() bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.loading
+}
+
+// Err returns the error of the last load, or nil if it succeeded.
+func (r *Resource[T]) Err() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.err
+}
+
+// Data returns the last data loaded, or nil before the first load finishes.
+// It is kept while refetching and when a refetch fails, so a stale view can
+// be shown while revalidating.
+func (r *Resource[T]) Data() *T {
+	track(r)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.data
+}
+
+// Refetch loads the data again, cancelling the load in flight.
+func (r *Resource[T]) Refetch() {
+	if r.ctx.Err() != nil {
+		return
+	}
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if r.options.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(r.ctx, r.options.Timeout)
+	} else {
+		ctx, cancel = context.WithCancel(r.ctx)
+	}
+	r.lock.Lock()
+	if r.cancelLoad != nil {
+		r.cancelLoad()
+	}
+	r.cancelLoad = cancel
+	r.generation++
+	generation := r.generation
+	r.loading = true
+	r.lock.Unlock()
+
+	sched.enqueue(func() {
+		sched.markDirty(r.owner)
+	})
+	go r.load(ctx, cancel, generation)
+}
+
+func (r *Resource[T]) load(ctx context.Context, cancel context.CancelFunc, generation int) {
+	defer cancel()
+	var data *T
+	var err error
+	if guard(r.owner.GetID(), func() { data, err = r.loader(ctx) }) {
+		err = errors.New("resource loader panicked")
+	}
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	sched.enqueue(func() {
+		r.lock.Lock()
+		if generation != r.generation || r.ctx.Err() != nil {
+			// Superseded by a refetch, or the component is gone
+			r.lock.Unlock()
+			return
+		}
+		r.loading = false
+		r.err = err
+		changed := err == nil
+		if changed {
+			r.data = data
+		}
+		dependents := make([]func(), 0, len(r.dependents))
+		for _, invalidate := range r.dependents {
+			dependents = append(dependents, invalidate)
+		}
+		r.lock.Unlock()
+
+		if err != nil {
+			logger.log("resource", WARNING, "Loading resource failed", ComponentID(r.owner.GetID()), Err(err))
+		} else if r.options.Key != "" {
+			cacheData(r.options.Key, data, r.options.CacheTime)
+		}
+		sched.markDirty(r.owner)
+		if changed {
+			for _, invalidate := range dependents {
+				invalidate()
+			}
+		}
+	})
+}
+
+func (r *Resource[T]) dependencyID() uuid.UUID {
+	return r.id
+}
+
+func (r *Resource[T]) addDependent(id uuid.UUID, invalidate func()) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.dependents[id] = invalidate
+}
+
+func (r *Resource[T]) removeDependent(id uuid.UUID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.dependents, id)
+}
@@ -0,0 +1,127 @@
+package goFE
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+// settle flushes until condition holds, for updates made by loaders running
+// on other goroutines.
+func settle(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+		Flush()
+	}
+}
+
+func TestResource_LoadsRefetchesAndFails(t *testing.T) {
+	resetResourceCache()
+	defer resetResourceCache()
+	memory := dom.NewMemory()
+	SetDocument(NewDocumentWithDOM(nil, memory))
+	defer SetDocument(nil)
+
+	owner := &selectProbe{id: uuid.New()}
+	defer killAllStates(owner)
+	responses := make(chan string)
+	resource := NewResource(owner, func(ctx context.Context) (*string, error) {
+		select {
+		case value := <-responses:
+			if value == "" {
+				return nil, errors.New("empty")
+			}
+			return &value, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}, &ResourceOptions{Key: "greeting"})
+	owner.value = func() string {
+		if data := resource.Data(); data != nil {
+			return *data
+		}
+		return "loading"
+	}
+	document.Append(owner)
+	document.Init()
+
+	if !resource.Loading() || memory.GetElementByID(owner.id.String()).TextContent() != "loading" {
+		t.Fatal("resource did not start out loading")
+	}
+	responses <- "hello"
+	settle(t, func() bool { return !resource.Loading() })
+	if got := memory.GetElementByID(owner.id.String()).TextContent(); got != "hello" {
+		t.Fatalf("rendered %q", got)
+	}
+
+	resource.Refetch()
+	if !resource.Loading() || *resource.Data() != "hello" {
+		t.Fatal("stale data not kept while refetching")
+	}
+	responses <- ""
+	settle(t, func() bool { return !resource.Loading() })
+	if resource.Err() == nil || *resource.Data() != "hello" {
+		t.Fatalf("after a failed refetch: err %v, data %q", resource.Err(), *resource.Data())
+	}
+
+	other := &selectProbe{id: uuid.New()}
+	defer killAllStates(other)
+	cached := NewResource(other, func(ctx context.Context) (*string, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, &ResourceOptions{Key: "greeting"})
+	if data := cached.Data(); data == nil || *data != "hello" || !cached.Loading() {
+		t.Fatal("cached data not shown while revalidating")
+	}
+}
+
+func TestResource_CacheExpires(t *testing.T) {
+	resetResourceCache()
+	defer resetResourceCache()
+	owner := &selectProbe{id: uuid.New()}
+	defer killAllStates(owner)
+	load := func(ctx context.Context) (*string, error) {
+		value := "hello"
+		return &value, nil
+	}
+	options := &ResourceOptions{Key: "expiring", CacheTime: 10 * time.Millisecond}
+	resource := NewResource(owner, load, options)
+	settle(t, func() bool { return !resource.Loading() })
+
+	time.Sleep(20 * time.Millisecond)
+	expired := NewResource(owner, func(ctx context.Context) (*string, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}, options)
+	if expired.Data() != nil {
+		t.Fatal("expired data was used")
+	}
+	if _, ok := resourceCache["expiring"]; ok {
+		t.Fatal("expired entry not evicted")
+	}
+}
+
+func TestResource_CancelledOnTeardown(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	cancelled := make(chan struct{})
+	NewResource(owner, func(ctx context.Context) (*int, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	}, nil)
+	killAllStates(owner)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("load not cancelled when the component was torn down")
+	}
+}
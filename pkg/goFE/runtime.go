@@ -0,0 +1,132 @@
+package goFE
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// global document
+var document *Document
+var logger = &Logger{Level: INFO}
+
+var startSchedulerOnce sync.Once
+
+func Init(loggerInit *Logger) {
+	if loggerInit != nil {
+		logger = loggerInit
+	} else {
+		logger = &Logger{Level: INFO}
+	}
+	startScheduler()
+}
+
+// startScheduler starts processing state updates and re-renders, unless
+// Init or Mount already did.
+func startScheduler() {
+	startSchedulerOnce.Do(func() {
+		go sched.run()
+	})
+}
+
+func SetDocument(doc *Document) {
+	document = doc
+}
+
+// GetDocument returns the document of the default app, set with
+// SetDocument. While a document runs code on behalf of its components (their
+// rendering, InitEventListeners, event handlers and state effects) it
+// returns that document instead, on the goroutine running that code, so
+// components work unchanged inside an app created with Mount.
+func GetDocument() *Document {
+	activeLock.Lock()
+	idle := len(active) == 0
+	activeLock.Unlock()
+	if idle {
+		return document
+	}
+	id := goroutineID()
+	activeLock.Lock()
+	defer activeLock.Unlock()
+	if entered := active[id]; len(entered) > 0 {
+		return entered[len(entered)-1]
+	}
+	return document
+}
+
+var activeLock sync.Mutex
+
+// active holds, per goroutine, the documents running code on behalf of their
+// components, innermost last.
+var active = make(map[uint64][]*Document)
+
+// enter makes d the document GetDocument returns on the calling goroutine
+// until the returned function is called.
+func (d *Document) enter() (leave func()) {
+	id := goroutineID()
+	activeLock.Lock()
+	active[id] = append(active[id], d)
+	activeLock.Unlock()
+	return func() {
+		activeLock.Lock()
+		defer activeLock.Unlock()
+		entered := active[id][:len(active[id])-1]
+		if len(entered) == 0 {
+			delete(active, id)
+			return
+		}
+		active[id] = entered
+	}
+}
+
+// documentOf returns the mounted document whose tree holds the component
+// with the given id, or nil.
+func documentOf(id uuid.UUID) *Document {
+	for _, d := range documents() {
+		if pathTo(d.GetComponentTree(), id) != nil {
+			return d
+		}
+	}
+	return nil
+}
+
+// goroutineID returns the id of the calling goroutine, as printed in the
+// header of its stack trace.
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if end := bytes.IndexByte(header, ' '); end >= 0 {
+		header = header[:end]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
+
+// documents returns the document of the default app, if any, followed by
+// those of the apps created with Mount.
+func documents() []*Document {
+	appsLock.Lock()
+	defer appsLock.Unlock()
+	var docs []*Document
+	if document != nil {
+		docs = append(docs, document)
+	}
+	for _, app := range apps {
+		if app.document != document {
+			docs = append(docs, app.document)
+		}
+	}
+	return docs
+}
+
+func RenderChildren(component Component) string {
+	var buffer string
+	for _, child := range component.GetChildren() {
+		buffer += renderComponent(child)
+	}
+	return buffer
+}
@@ -0,0 +1,235 @@
+package goFE
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// maxFlushPasses bounds how many rounds of updates and effects a single flush
+// will run, so an effect that always sets state cannot hang the page.
+const maxFlushPasses = 100
+
+// scheduler is the single cooperative runtime behind every State. setState
+// calls only queue work; once per animation frame one goroutine drains the
+// queue in order, applying updates, then running the effects they triggered
+// (which may queue further updates), and finally rendering each affected
+// component once.
+type scheduler struct {
+	// flushing is held for the duration of a flush, so an explicit Flush
+	// cannot overlap with the one run on an animation frame.
+	flushing sync.Mutex
+	lock     sync.Mutex
+	updates  []func()
+	effects  []func()
+	dirty    map[uuid.UUID]bool
+	// batchDepth is non-zero while inside Batch. Flushes leave queued updates
+	// alone meanwhile, and the batch wakes the scheduler when it ends.
+	batchDepth int
+	// replaying is set while an update re-applied by a Replayer runs.
+	replaying bool
+	wake      chan struct{}
+}
+
+var sched = newScheduler()
+
+func newScheduler() *scheduler {
+	return &scheduler{
+		dirty: make(map[uuid.UUID]bool),
+		wake:  make(chan struct{}, 1),
+	}
+}
+
+// run processes queued work until the program exits. It is started by Init.
+// Updates arriving before the next animation frame are coalesced into a
+// single flush.
+func (s *scheduler) run() {
+	for range s.wake {
+		waitForAnimationFrame()
+		s.flush()
+	}
+}
+
+// waitForAnimationFrame blocks until the page is about to paint the next
+// frame. Without a document there is nothing to paint.
+func waitForAnimationFrame() {
+	docs := documents()
+	if len(docs) == 0 {
+		return
+	}
+	done := make(chan struct{})
+	docs[0].dom.RequestAnimationFrame(func() {
+		close(done)
+	})
+	<-done
+}
+
+// enqueue adds a state update to the queue and wakes the scheduler.
+func (s *scheduler) enqueue(update func()) {
+	s.lock.Lock()
+	s.updates = append(s.updates, update)
+	batching := s.batchDepth > 0
+	s.lock.Unlock()
+	if !batching {
+		s.notify()
+	}
+}
+
+// queueEffect schedules an effect of a state owned by owner to run after the
+// current round of updates. It is only called from the scheduler goroutine
+// while applying an update.
+func (s *scheduler) queueEffect(owner Component, effect func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.replaying {
+		return
+	}
+	s.effects = append(s.effects, func() {
+		// Effects see the document of their owner's app
+		if d := documentOf(owner.GetID()); d != nil {
+			defer d.enter()()
+		}
+		guard(owner.GetID(), effect)
+	})
+}
+
+// replay runs apply, an update re-applied by a Replayer, without queueing
+// the effects it triggers or recording it. It runs on the scheduler.
+func (s *scheduler) replay(apply func()) {
+	s.lock.Lock()
+	s.replaying = true
+	s.lock.Unlock()
+	defer func() {
+		s.lock.Lock()
+		s.replaying = false
+		s.lock.Unlock()
+	}()
+	apply()
+}
+
+// isReplaying reports whether an update re-applied by a Replayer is running.
+func (s *scheduler) isReplaying() bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.replaying
+}
+
+// markDirty schedules component to be rendered at the end of the flush.
+func (s *scheduler) markDirty(component Component) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.dirty[component.GetID()] = true
+}
+
+func (s *scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+		// A flush is already pending and will pick this work up
+	}
+}
+
+// flush applies all pending updates and the effects they trigger until the
+// queue settles, then renders each dirty component once. Updates queued while
+// a Batch is running are left for the flush after it ends.
+func (s *scheduler) flush() {
+	s.flushing.Lock()
+	defer s.flushing.Unlock()
+	for pass := 0; ; pass++ {
+		s.lock.Lock()
+		var updates []func()
+		if s.batchDepth == 0 {
+			updates = s.updates
+			s.updates = nil
+		}
+		s.lock.Unlock()
+		for _, update := range updates {
+			update()
+		}
+
+		s.lock.Lock()
+		effects := s.effects
+		s.effects = nil
+		s.lock.Unlock()
+		for _, effect := range effects {
+			effect()
+		}
+
+		if len(updates) == 0 && len(effects) == 0 {
+			break
+		}
+		if pass == maxFlushPasses {
+			logger.log("scheduler", ERROR, "State updates did not settle, dropping the rest", Int("passes", maxFlushPasses))
+			s.lock.Lock()
+			s.updates, s.effects = nil, nil
+			s.lock.Unlock()
+			break
+		}
+	}
+
+	// Rendering only marks components dirty when an ErrorBoundary catches a
+	// panic, so this settles once every fallback is rendered
+	for pass := 0; pass < maxFlushPasses; pass++ {
+		s.lock.Lock()
+		dirty := s.dirty
+		s.dirty = make(map[uuid.UUID]bool)
+		s.lock.Unlock()
+		docs := documents()
+		if len(docs) == 0 || len(dirty) == 0 {
+			return
+		}
+		for _, d := range docs {
+			d.renderDirty(dirty)
+		}
+	}
+}
+
+// Flush applies pending state updates and renders the result straight away
+// instead of on the next animation frame. It is mostly useful in tests, and
+// must not be called from an effect. Inside Batch it applies nothing.
+func Flush() {
+	sched.flush()
+}
+
+// Batch runs fn and holds back every state update it makes until it returns,
+// so they are applied together and rendered once. Updates made by other
+// goroutines while fn runs are held back too.
+func Batch(fn func()) {
+	sched.lock.Lock()
+	sched.batchDepth++
+	sched.lock.Unlock()
+	defer func() {
+		sched.lock.Lock()
+		sched.batchDepth--
+		pending := sched.batchDepth == 0 && len(sched.updates) > 0
+		sched.lock.Unlock()
+		if pending {
+			sched.notify()
+		}
+	}()
+	fn()
+}
+
+// Atomic queues fn to run on the scheduler as a single update, for
+// read-modify-write across several states. While fn runs no other update is
+// applied, so states read with Get are consistent with each other, and the
+// updates fn makes (through setState or Update) are applied as soon as it
+// returns, before anything queued earlier is. Like effects, fn must not block.
+func Atomic(fn func()) {
+	sched.enqueue(func() {
+		sched.lock.Lock()
+		queued := sched.updates
+		sched.updates = nil
+		sched.lock.Unlock()
+
+		fn()
+
+		sched.lock.Lock()
+		updates := sched.updates
+		sched.updates = queued
+		sched.lock.Unlock()
+		for _, update := range updates {
+			update()
+		}
+	})
+}
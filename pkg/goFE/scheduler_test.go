@@ -0,0 +1,141 @@
+package goFE
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+// orderProbe logs its renders to log.
+type orderProbe struct {
+	id       uuid.UUID
+	log      *[]string
+	state    *State[int]
+	setState func(*int)
+}
+
+func newOrderProbe(log *[]string) *orderProbe {
+	probe := &orderProbe{id: uuid.New(), log: log}
+	probe.state, probe.setState = NewState[int](probe, new(int))
+	return probe
+}
+
+func (p *orderProbe) Render() string {
+	*p.log = append(*p.log, "render "+strconv.Itoa(*p.state.Value))
+	return `<div id="` + p.id.String() + `">` + strconv.Itoa(*p.state.Value) + `</div>`
+}
+func (p *orderProbe) GetID() uuid.UUID         { return p.id }
+func (p *orderProbe) GetChildren() []Component { return nil }
+func (p *orderProbe) InitEventListeners()      {}
+
+func TestScheduler_UpdateEffectRenderOrder(t *testing.T) {
+	var log []string
+	probe := newOrderProbe(&log)
+	SetDocument(NewDocumentWithDOM([]Component{probe}, dom.NewMemory()))
+	defer SetDocument(nil)
+	document.Init()
+	defer killAllStates(probe)
+	probe.state.AddEffect(func(value *int) {
+		log = append(log, "effect "+strconv.Itoa(*value))
+		if *value == 1 {
+			two := 2
+			probe.setState(&two)
+		}
+	})
+
+	log = nil
+	for i := 0; i < 3; i++ {
+		probe.state.Update(func(prev *int) *int {
+			log = append(log, "update")
+			return prev
+		})
+	}
+	one := 1
+	probe.setState(&one)
+	Flush()
+
+	// Every update and the effects they trigger settle before a single render
+	want := "update, update, update, effect 0, effect 0, effect 0, effect 1, effect 2, render 2"
+	if got := strings.Join(log, ", "); got != want {
+		t.Fatalf("flush ran\n %s\nwant\n %s", got, want)
+	}
+}
+
+func TestScheduler_EffectLoopIsBounded(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	count, setCount := NewState[int](owner, new(int))
+	defer killAllStates(owner)
+	runs := 0
+	count.AddEffect(func(value *int) {
+		runs++
+		next := *value + 1
+		setCount(&next)
+	})
+
+	setCount(new(int))
+	Flush()
+	if runs == 0 || runs > maxFlushPasses+1 {
+		t.Fatalf("the effect ran %d times, want at most %d", runs, maxFlushPasses+1)
+	}
+
+	// The dropped work does not carry over into the next flush
+	runs = 0
+	Flush()
+	if runs != 0 {
+		t.Fatalf("the effect ran %d times after the loop was stopped", runs)
+	}
+}
+
+func TestScheduler_DropsUpdatesToKilledStates(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	count, setCount := NewState[int](owner, new(int))
+	effects := 0
+	count.AddEffect(func(*int) { effects++ })
+
+	one := 1
+	setCount(&one)
+	killAllStates(owner)
+	count.Update(func(prev *int) *int {
+		t.Fatal("updater ran for a killed state")
+		return prev
+	})
+	Flush()
+	if *count.Get() != 0 || effects != 0 {
+		t.Fatalf("count = %d after %d effects, want the update dropped", *count.Get(), effects)
+	}
+}
+
+func TestBatch_FlushMidBatchWaits(t *testing.T) {
+	var log []string
+	probe := newOrderProbe(&log)
+	SetDocument(NewDocumentWithDOM([]Component{probe}, dom.NewMemory()))
+	defer SetDocument(nil)
+	document.Init()
+	defer killAllStates(probe)
+
+	log = nil
+	Batch(func() {
+		one := 1
+		probe.setState(&one)
+		// A flush landing while the batch is blocked, as the one run on an
+		// animation frame would
+		done := make(chan struct{})
+		go func() {
+			sched.flush()
+			close(done)
+		}()
+		<-done
+		if *probe.state.Get() != 0 || len(log) != 0 {
+			t.Errorf("a flush mid-batch applied part of it: value %d, renders %v", *probe.state.Get(), log)
+		}
+		two := 2
+		probe.setState(&two)
+	})
+	Flush()
+	if *probe.state.Get() != 2 || strings.Join(log, ", ") != "render 2" {
+		t.Fatalf("value %d, renders %v, want the batch rendered once", *probe.state.Get(), log)
+	}
+}
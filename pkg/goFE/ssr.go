@@ -0,0 +1,142 @@
+package goFE
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// StateScriptID is the id of the script element RenderToString emits to carry
+// component state to the client.
+const StateScriptID = "gofe-state"
+
+// ServerRender is the output of RenderToString.
+type ServerRender struct {
+	// HTML is the rendered component tree, to be placed inside #root.
+	HTML string
+	// State is a <script type="application/json"> element holding the
+	// serialized state of every component, to be placed anywhere in the page.
+	State string
+}
+
+// RenderToString renders a component tree to HTML for a server response, in
+// the same way Document.Init would render it into #root, along with the state
+// Document.Hydrate needs to pick it up on the client. It does not use
+// syscall/js, so it can be called from an ordinary Go HTTP handler.
+//
+// State values are serialized with encoding/json, so only exported fields
+// survive the round trip. The states are killed once rendered; build a new
+// tree for every request. Concurrent calls, such as those of an HTTP
+// server, render independently of each other.
+func RenderToString(components ...Component) (*ServerRender, error) {
+	tree := &serverTree{roots: components}
+	serverTreesLock.Lock()
+	serverTrees[tree] = true
+	serverTreesLock.Unlock()
+	html := renderComponents(components)
+	serverTreesLock.Lock()
+	delete(serverTrees, tree)
+	serverTreesLock.Unlock()
+	state, err := serializeStates(components)
+	for _, component := range components {
+		killAllStates(component)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// json.Marshal escapes <, > and &, so the payload cannot close the script
+	return &ServerRender{
+		HTML:  html,
+		State: `<script type="application/json" id="` + StateScriptID + `">` + state + `</script>`,
+	}, nil
+}
+
+// serverTree is a component tree RenderToString is rendering.
+type serverTree struct {
+	roots []Component
+}
+
+var serverTreesLock sync.Mutex
+
+// serverTrees holds the trees being rendered by RenderToString calls.
+var serverTrees = make(map[*serverTree]bool)
+
+func renderComponents(components []Component) string {
+	var buffer string
+	for _, component := range components {
+		buffer += renderComponent(component)
+	}
+	return buffer
+}
+
+// walkTree calls fn for every component in the tree with its path, the
+// dot-separated child indexes leading to it (e.g. "0.2.1"). Component ids
+// differ between the server and the client, but paths are the same as long as
+// both build the same tree.
+func walkTree(components []Component, prefix string, fn func(path string, component Component) error) error {
+	for i, component := range components {
+		path := strconv.Itoa(i)
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		if err := fn(path, component); err != nil {
+			return err
+		}
+		if err := walkTree(component.GetChildren(), path, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serializeStates encodes the states of every component as a JSON object
+// mapping tree paths to the component's state values in creation order.
+func serializeStates(components []Component) (string, error) {
+	snapshot := make(map[string][]json.RawMessage)
+	err := walkTree(components, "", func(path string, component Component) error {
+		registryLock.Lock()
+		states := append([]stateHandle{}, componentStates[component.GetID()]...)
+		registryLock.Unlock()
+		for i, state := range states {
+			data, err := state.marshal()
+			if err != nil {
+				return fmt.Errorf("serializing state %d of component %s: %w", i, path, err)
+			}
+			snapshot[path] = append(snapshot[path], data)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// restoreStates loads a payload produced by serializeStates into the states
+// of an identically shaped tree.
+func restoreStates(components []Component, payload string) error {
+	var snapshot map[string][]json.RawMessage
+	if err := json.Unmarshal([]byte(payload), &snapshot); err != nil {
+		return err
+	}
+	return walkTree(components, "", func(path string, component Component) error {
+		values := snapshot[path]
+		registryLock.Lock()
+		states := append([]stateHandle{}, componentStates[component.GetID()]...)
+		registryLock.Unlock()
+		if len(values) != len(states) {
+			return fmt.Errorf("component %s has %d states, server sent %d", path, len(states), len(values))
+		}
+		for i, state := range states {
+			if err := state.restore(values[i]); err != nil {
+				return fmt.Errorf("restoring state %d of component %s: %w", i, path, err)
+			}
+		}
+		return nil
+	})
+}
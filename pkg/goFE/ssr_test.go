@@ -0,0 +1,54 @@
+package goFE
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+type ssrState struct {
+	Count int
+	Label string
+}
+
+type ssrComponent struct {
+	id       uuid.UUID
+	state    *State[ssrState]
+	children []Component
+}
+
+func newSSRComponent(value ssrState, children ...Component) *ssrComponent {
+	component := &ssrComponent{id: uuid.New(), children: children}
+	component.state, _ = NewState[ssrState](component, &value)
+	return component
+}
+
+func (c *ssrComponent) Render() string {
+	return `<div id="` + c.id.String() + `">` + c.state.Value.Label + RenderChildren(c) + `</div>`
+}
+func (c *ssrComponent) GetID() uuid.UUID         { return c.id }
+func (c *ssrComponent) GetChildren() []Component { return c.children }
+func (c *ssrComponent) InitEventListeners()      {}
+
+func TestRenderToString_RestoresIntoNewTree(t *testing.T) {
+	server := newSSRComponent(ssrState{Count: 1, Label: "parent"},
+		newSSRComponent(ssrState{Count: 2, Label: "</script>"}))
+	rendered, err := RenderToString(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(rendered.State, "</script>") != 1 {
+		t.Fatalf("state payload is not escaped: %s", rendered.State)
+	}
+
+	client := newSSRComponent(ssrState{}, newSSRComponent(ssrState{}))
+	payload := strings.TrimSuffix(rendered.State[strings.Index(rendered.State, ">")+1:], "</script>")
+	if err := restoreStates([]Component{client}, payload); err != nil {
+		t.Fatal(err)
+	}
+	child := client.children[0].(*ssrComponent)
+	if client.state.Value.Count != 1 || child.state.Value.Count != 2 || child.state.Value.Label != "</script>" {
+		t.Fatalf("restored %+v and %+v", *client.state.Value, *child.state.Value)
+	}
+}
@@ -1,130 +1,265 @@
 package goFE
 
 import (
-	"github.com/google/uuid"
+	"encoding/json"
 	"sync"
-	"time"
+
+	"github.com/google/uuid"
 )
 
-var stateLock sync.Mutex
-var stateKillChannels map[uuid.UUID]map[uuid.UUID]chan bool
+// stateHandle is the type-erased view of a State the registry needs.
+type stateHandle interface {
+	kill()
+	// marshal and restore carry the value across a server render; see
+	// RenderToString and Document.Hydrate.
+	marshal() ([]byte, error)
+	restore(data []byte) error
+	// replay queues an update to a value recorded by a Recorder, which runs
+	// no effects and is not recorded.
+	replay(data []byte) error
+}
 
-func init() {
-	stateKillChannels = make(map[uuid.UUID]map[uuid.UUID]chan bool)
-	go func() {
-		for {
-			total := 0
-			for _, componentMap := range stateKillChannels {
-				total += len(componentMap)
-			}
-			println("Total number of kill channels", total)
-			<-time.After(5 * time.Second)
-		}
-	}()
+var registryLock sync.Mutex
+
+// componentStates holds the states of each live component, in creation order.
+var componentStates = make(map[uuid.UUID][]stateHandle)
+
+func registerState(component Component, state stateHandle) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	componentStates[component.GetID()] = append(componentStates[component.GetID()], state)
 }
 
-func registerComponentIfNotExists(component Component) {
-	println("Registering component if not exists, componentID: ", component.GetID().String())
-	if _, ok := stateKillChannels[component.GetID()]; !ok {
-		stateKillChannels[component.GetID()] = make(map[uuid.UUID]chan bool)
-	}
-	println("Registered component: ", component.GetID().String())
+var teardownLock sync.Mutex
+
+// teardowns holds functions to run when a component's states are killed.
+var teardowns = make(map[uuid.UUID][]func())
+
+// onTeardown arranges for fn to run when component is torn down.
+func onTeardown(component Component, fn func()) {
+	teardownLock.Lock()
+	defer teardownLock.Unlock()
+	teardowns[component.GetID()] = append(teardowns[component.GetID()], fn)
 }
 
-func registerKillChannel[T any](component Component, state *State[T]) {
-	logger.Log(DEBUG, "Registering kill channel, componentID: "+component.GetID().String())
-	stateLock.Lock()
-	defer stateLock.Unlock()
-	registerComponentIfNotExists(component)
-	ch := make(chan bool)
-	stateKillChannels[component.GetID()][state.id] = ch
-	state.kill = ch
+func runTeardowns(component Component) {
+	teardownLock.Lock()
+	fns := teardowns[component.GetID()]
+	delete(teardowns, component.GetID())
+	teardownLock.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
 }
 
+// killAllStates tears down component and its descendants: they are
+// unmounted, their event handlers are released and their states stop
+// accepting updates.
 func killAllStates(component Component) {
-	logger.Log(DEBUG, "Killing all states, componentID: "+component.GetID().String())
-	stateLock.Lock()
-	defer stateLock.Unlock()
-	if _, ok := stateKillChannels[component.GetID()]; ok {
-		for _, killCh := range stateKillChannels[component.GetID()] {
-			killCh <- true
-		}
-		delete(stateKillChannels, component.GetID())
-	} else {
-		logger.Log(WARNING, "No states to kill, componentID: "+component.GetID().String())
+	logger.log("state", DEBUG, "Killing all states", ComponentID(component.GetID()))
+	for _, d := range documents() {
+		d.unmount(component)
+		d.releaseListeners(component)
+	}
+	killStates(component)
+}
+
+func killStates(component Component) {
+	registryLock.Lock()
+	states := componentStates[component.GetID()]
+	delete(componentStates, component.GetID())
+	registryLock.Unlock()
+	for _, state := range states {
+		state.kill()
+	}
+	runTeardowns(component)
+	forgetRenderStats(component.GetID())
+	for _, child := range component.GetChildren() {
+		killStates(child)
 	}
 }
 
-// State is a generic struct that holds a value and a channel
+// State holds a value owned by a component. Updates are queued on the
+// scheduler and applied in order, after which the state's effects run and
+// the owning component is re-rendered.
 type State[T any] struct {
-	Value     *T
-	id        uuid.UUID
-	ch        chan *T
-	lock      sync.Mutex
-	listeners map[uuid.UUID]func(value *T)
-	kill      chan bool
+	Value   *T
+	id      uuid.UUID
+	owner   Component
+	lock    sync.Mutex
+	effects []func(value *T)
+	// observers are called as each update is applied, before the next one.
+	observers []func(value *T)
+	killed    bool
+	// dependents are the Computed values that read this state.
+	dependents map[uuid.UUID]func()
 }
 
 // NewState creates a new instance of frontend state. It returns a pointer to the
 // new state, with initial value, and a function to set the state.
 func NewState[T any](component Component, value *T) (*State[T], func(*T)) {
-	if &stateLock == nil {
-		stateLock = sync.Mutex{}
-	}
-	logger.Log(DEBUG, "Creating new state, componentID: "+component.GetID().String())
 	newState := &State[T]{
-		Value:     value,
-		id:        uuid.New(),
-		ch:        make(chan *T),
-		listeners: make(map[uuid.UUID]func(value *T)),
+		Value: value,
+		id:    uuid.New(),
+		owner: component,
 	}
+	logger.log("state", DEBUG, "Creating new state", ComponentID(component.GetID()), StateID(newState.id))
 	setState := func(newValue *T) {
-		newState.ch <- newValue
+		sched.enqueue(func() {
+			newState.set(newValue)
+		})
 	}
-	registerKillChannel(component, newState)
-	go listenForStateChange[T](component, newState)
+	registerState(component, newState)
 	return newState, setState
 }
 
 // AddEffect adds an effect to the state. An effect is a function that is called
-// whenever the state changes.
+// whenever the state changes. Effects run on the scheduler in the order they
+// were added, after the update that triggered them; long-running work should
+// be started in a goroutine.
 func (s *State[T]) AddEffect(effect func(value *T)) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
-	s.listeners[uuid.New()] = effect
-}
-
-// listenForStateChange listens for state changes and updates the state accordingly.
-func listenForStateChange[T any](component Component, state *State[T]) {
-	logger.Log(DEBUG, "Listening for state change, componentID: "+component.GetID().String())
-	for {
-		select {
-		case value := <-state.ch:
-			state.lock.Lock()
-			//println("State change detected, componentID: ", component.GetID().String())
-			state.Value = value
-			state.lock.Unlock()
-			document.renderNotifier <- component
-			notifyListeners[T](state)
-		case <-state.kill:
-			//println("Stopped listening for state change, componentID: ", component.GetID().String())
-			// kill child components
-			for _, child := range component.GetChildren() {
-				killAllStates(child)
-			}
-			logger.Log(DEBUG, "Stopped listening for state change, componentID: "+component.GetID().String())
-			return
-		}
+	s.effects = append(s.effects, effect)
+}
+
+// Get returns the current value. Reading a state through Get inside the
+// function of a Computed makes the computed value depend on it.
+func (s *State[T]) Get() *T {
+	s.lock.Lock()
+	value := s.Value
+	s.lock.Unlock()
+	track(s)
+	return value
+}
+
+// observe adds a function called on the scheduler with every new value, as
+// soon as it is applied rather than with the effects.
+func (s *State[T]) observe(observer func(value *T)) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.observers = append(s.observers, observer)
+}
+
+func (s *State[T]) dependencyID() uuid.UUID {
+	return s.id
+}
+
+func (s *State[T]) addDependent(id uuid.UUID, invalidate func()) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.dependents == nil {
+		s.dependents = make(map[uuid.UUID]func())
+	}
+	s.dependents[id] = invalidate
+}
+
+func (s *State[T]) removeDependent(id uuid.UUID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.dependents, id)
+}
+
+// Update queues a functional update: updater receives the latest value,
+// including every update queued before it, and returns the new one. Unlike
+// setState with a value read from Value, concurrent updates are never lost.
+// updater runs on the scheduler while the state is locked, so it must not
+// read or update the same state.
+func (s *State[T]) Update(updater func(prev *T) *T) {
+	sched.enqueue(func() {
+		s.apply(updater)
+	})
+}
+
+// set applies a queued update. It runs on the scheduler.
+func (s *State[T]) set(value *T) {
+	s.apply(func(*T) *T {
+		return value
+	})
+}
+
+// apply replaces the value with the result of updater, then marks the owner
+// dirty and queues the effects. It runs on the scheduler.
+func (s *State[T]) apply(updater func(prev *T) *T) {
+	s.lock.Lock()
+	if s.killed {
+		s.lock.Unlock()
+		logger.log("state", DEBUG, "Ignoring update to killed state", ComponentID(s.owner.GetID()), StateID(s.id))
+		return
+	}
+	recorder := activeRecorder()
+	var previous []byte
+	if recorder != nil {
+		// Encoded now, as updater may change the previous value in place
+		previous, _ = json.Marshal(s.Value)
+	}
+	value := updater(s.Value)
+	s.Value = value
+	effects := append([]func(value *T){}, s.effects...)
+	observers := append([]func(value *T){}, s.observers...)
+	dependents := make([]func(), 0, len(s.dependents))
+	for _, invalidate := range s.dependents {
+		dependents = append(dependents, invalidate)
+	}
+	s.lock.Unlock()
+
+	if recorder != nil {
+		recordStateChange(recorder, s.owner, s, s.id, previous, value)
+	}
+	for _, observer := range observers {
+		observer(value)
+	}
+	sched.markDirty(s.owner)
+	for _, invalidate := range dependents {
+		invalidate()
 	}
+	for _, effect := range effects {
+		effect := effect
+		sched.queueEffect(s.owner, func() {
+			effect(value)
+		})
+	}
+}
+
+func (s *State[T]) kill() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.killed = true
+	s.effects = nil
+	s.observers = nil
+	s.dependents = nil
 }
 
-// notifyListeners notifies all listeners of a state change.
-func notifyListeners[T any](state *State[T]) {
-	state.lock.Lock()
-	defer state.lock.Unlock()
-	for _, listener := range state.listeners {
-		go listener(state.Value)
+func (s *State[T]) marshal() ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return json.Marshal(s.Value)
+}
+
+// restore replaces the value without queueing an update or running effects;
+// it is only used before the component is first mounted.
+func (s *State[T]) restore(data []byte) error {
+	var value *T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
 	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.Value = value
+	return nil
+}
+
+func (s *State[T]) replay(data []byte) error {
+	var value *T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	sched.enqueue(func() {
+		sched.replay(func() {
+			s.set(value)
+		})
+	})
+	return nil
 }
 
 // UpdateComponentArray provides functionality to control a variable-length collection of components,
@@ -163,3 +298,53 @@ func UpdateComponentArray[T Component, Props any](input *[]T, newLen int, newT f
 		}
 	}
 }
+
+// KeyedComponent is a component that can be matched across updates of a
+// component array by a stable key, and can receive new props without being
+// rebuilt.
+type KeyedComponent[Props any] interface {
+	Component
+	Key() string
+	SetProps(props *Props)
+}
+
+// UpdateKeyedComponentArray reconciles a list of keyed children against
+// newProps. Children whose key is still present are kept (with their state)
+// and moved into the new order, and receive their new props via SetProps.
+// Children whose key disappeared have their states killed, and props with an
+// unseen key get a new component from newT.
+func UpdateKeyedComponentArray[T KeyedComponent[Props], Props any](input *[]T, newProps []*Props, keyOf func(props *Props) string, newT func(props *Props) T) {
+	if input == nil {
+		panic("'UpdateKeyedComponentArray' input cannot be nil")
+	}
+	existing := make(map[string]T, len(*input))
+	for _, component := range *input {
+		key := component.Key()
+		if _, duplicate := existing[key]; duplicate {
+			logger.log("state", WARNING, "Duplicate key in component array, discarding", String("key", key), ComponentID(component.GetID()))
+			killAllStates(component)
+			continue
+		}
+		existing[key] = component
+	}
+
+	output := make([]T, 0, len(newProps))
+	for _, props := range newProps {
+		key := keyOf(props)
+		if component, ok := existing[key]; ok {
+			delete(existing, key)
+			component.SetProps(props)
+			output = append(output, component)
+			continue
+		}
+		output = append(output, newT(props))
+	}
+
+	// Whatever is left over was not matched by any of the new props
+	for _, component := range *input {
+		if removed, ok := existing[component.Key()]; ok && any(removed) == any(component) {
+			killAllStates(component)
+		}
+	}
+	*input = output
+}
@@ -0,0 +1,143 @@
+package goFE
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestState_UpdateIsNotLost(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	count, _ := NewState[int](owner, new(int))
+	defer killAllStates(owner)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			count.Update(func(prev *int) *int {
+				next := *prev + 1
+				return &next
+			})
+		}()
+	}
+	wg.Wait()
+	Flush()
+	if got := *count.Get(); got != 50 {
+		t.Fatalf("count = %d, want 50", got)
+	}
+}
+
+func TestAtomic_TransfersConsistently(t *testing.T) {
+	owner := &selectProbe{id: uuid.New()}
+	from, setFrom := NewState[int](owner, new(int))
+	to, setTo := NewState[int](owner, new(int))
+	defer killAllStates(owner)
+	hundred := 100
+	setFrom(&hundred)
+	for i := 0; i < 10; i++ {
+		Atomic(func() {
+			remaining, moved := *from.Get()-10, *to.Get()+10
+			setFrom(&remaining)
+			setTo(&moved)
+		})
+	}
+	Flush()
+
+	if *from.Get() != 0 || *to.Get() != 100 {
+		t.Fatalf("from = %d, to = %d", *from.Get(), *to.Get())
+	}
+}
+
+type rowProps struct {
+	key   string
+	label string
+}
+
+type row struct {
+	id       uuid.UUID
+	props    *rowProps
+	count    *State[int]
+	setCount func(*int)
+}
+
+func newRow(props *rowProps) *row {
+	r := &row{id: uuid.New(), props: props}
+	r.count, r.setCount = NewState[int](r, new(int))
+	return r
+}
+
+func (r *row) Render() string {
+	return `<li id="` + r.id.String() + `">` + r.props.label + `</li>`
+}
+func (r *row) GetID() uuid.UUID         { return r.id }
+func (r *row) GetChildren() []Component { return nil }
+func (r *row) InitEventListeners()      {}
+func (r *row) Key() string              { return r.props.key }
+func (r *row) SetProps(props *rowProps) { r.props = props }
+
+func rowKey(props *rowProps) string { return props.key }
+
+func rowsProps(keys ...string) []*rowProps {
+	props := make([]*rowProps, len(keys))
+	for i, key := range keys {
+		props[i] = &rowProps{key: key, label: key + "!"}
+	}
+	return props
+}
+
+// alive reports whether component's states are still registered.
+func alive(component Component) bool {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	_, ok := componentStates[component.GetID()]
+	return ok
+}
+
+func TestUpdateKeyedComponentArray_KeepsReordersAndRemoves(t *testing.T) {
+	var rows []*row
+	UpdateKeyedComponentArray(&rows, rowsProps("a", "b", "c"), rowKey, newRow)
+	a, b, c := rows[0], rows[1], rows[2]
+	defer killAllStates(a)
+	defer killAllStates(c)
+	five := 5
+	a.setCount(&five)
+	Flush()
+
+	UpdateKeyedComponentArray(&rows, rowsProps("c", "d", "a"), rowKey, newRow)
+	defer killAllStates(rows[1])
+	if len(rows) != 3 || rows[0] != c || rows[2] != a || rows[1] == b {
+		t.Fatalf("rows were not kept and reordered by key: %v", rows)
+	}
+	if *a.count.Get() != 5 {
+		t.Fatalf("kept row lost its state: count = %d", *a.count.Get())
+	}
+	if a.props.label != "a!" || a.props != rows[2].props || rows[1].props.key != "d" {
+		t.Fatal("kept rows did not receive their new props")
+	}
+	if alive(b) || !alive(a) || !alive(rows[1]) {
+		t.Fatal("only the row whose key disappeared should be killed")
+	}
+
+	b.setCount(&five)
+	Flush()
+	if *b.count.Get() != 0 {
+		t.Fatal("the removed row's state still accepts updates")
+	}
+}
+
+func TestUpdateKeyedComponentArray_DuplicateKeys(t *testing.T) {
+	first, second := newRow(&rowProps{key: "a"}), newRow(&rowProps{key: "a"})
+	rows := []*row{first, second}
+	defer killAllStates(first)
+
+	UpdateKeyedComponentArray(&rows, rowsProps("a"), rowKey, newRow)
+	if len(rows) != 1 || rows[0] != first {
+		t.Fatalf("rows = %v, want only the first row with the key", rows)
+	}
+	if alive(second) || !alive(first) {
+		t.Fatal("the duplicate should be killed and the first kept")
+	}
+}
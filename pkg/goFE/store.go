@@ -0,0 +1,128 @@
+package goFE
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Reducer computes the next value of a Store from the current one and an
+// action. It must not modify current.
+type Reducer[T any] func(current *T, action any) *T
+
+// Middleware wraps every action dispatched to a Store. It calls next to pass
+// the action on (to the next middleware, and finally the reducer), and may
+// inspect the store before and after, replace the action or drop it.
+type Middleware[T any] func(store *Store[T], action any, next func(action any))
+
+// Store holds a value shared by many components, changed only by dispatching
+// actions through its reducer. Components subscribe to slices of it with
+// Select and are re-rendered only when their slice changes.
+type Store[T any] struct {
+	lock       sync.Mutex
+	value      *T
+	reducer    Reducer[T]
+	middleware []Middleware[T]
+	selections map[uuid.UUID]func()
+}
+
+// NewStore creates a store holding initial, updated by reducer. Middleware
+// sees each action in the order given.
+func NewStore[T any](initial *T, reducer Reducer[T], middleware ...Middleware[T]) *Store[T] {
+	return &Store[T]{
+		value:      initial,
+		reducer:    reducer,
+		middleware: middleware,
+		selections: make(map[uuid.UUID]func()),
+	}
+}
+
+// Get returns the current value. Treat it as read-only; change it by
+// dispatching an action.
+func (s *Store[T]) Get() *T {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.value
+}
+
+// Dispatch queues action on the scheduler, like a setState call. Once it has
+// been through the middleware and reducer, every component whose selected
+// slice changed is re-rendered.
+func (s *Store[T]) Dispatch(action any) {
+	sched.enqueue(func() {
+		s.dispatch(0, action)
+	})
+}
+
+func (s *Store[T]) dispatch(i int, action any) {
+	if i < len(s.middleware) {
+		s.middleware[i](s, action, func(action any) {
+			s.dispatch(i+1, action)
+		})
+		return
+	}
+	s.lock.Lock()
+	s.value = s.reducer(s.value, action)
+	selections := make([]func(), 0, len(s.selections))
+	for _, check := range s.selections {
+		selections = append(selections, check)
+	}
+	s.lock.Unlock()
+	for _, check := range selections {
+		check()
+	}
+}
+
+// Selection is the slice of a Store a component depends on.
+type Selection[S any] struct {
+	lock  sync.Mutex
+	value S
+}
+
+// Value returns the selected slice as of the last dispatch.
+func (s *Selection[S]) Value() S {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.value
+}
+
+// Select subscribes component to the slice of store picked by selector. After
+// each action the selector runs again, and component is re-rendered if the
+// result differs (by reflect.DeepEqual) from the previous one. Like a State,
+// the selection belongs to component and ends when it is torn down.
+func Select[T, S any](store *Store[T], component Component, selector func(value *T) S) *Selection[S] {
+	selection := &Selection[S]{value: selector(store.Get())}
+	subscription := uuid.New()
+	store.lock.Lock()
+	store.selections[subscription] = func() {
+		next := selector(store.Get())
+		selection.lock.Lock()
+		changed := !reflect.DeepEqual(selection.value, next)
+		selection.value = next
+		selection.lock.Unlock()
+		if changed {
+			sched.markDirty(component)
+		}
+	}
+	store.lock.Unlock()
+	onTeardown(component, func() {
+		store.lock.Lock()
+		delete(store.selections, subscription)
+		store.lock.Unlock()
+	})
+	return selection
+}
+
+// LogActions returns middleware that logs the type of every action at DEBUG
+// level.
+func LogActions[T any]() Middleware[T] {
+	return func(store *Store[T], action any, next func(action any)) {
+		name := "<nil>"
+		if action != nil {
+			name = reflect.TypeOf(action).String()
+		}
+		logger.log("store", DEBUG, "Dispatching action", String("action", name))
+		next(action)
+	}
+}
@@ -0,0 +1,91 @@
+package goFE
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+type session struct {
+	User   string
+	Unread int
+}
+
+type login struct{ user string }
+type receive struct{}
+
+func reduceSession(current *session, action any) *session {
+	next := *current
+	switch action := action.(type) {
+	case login:
+		next.User = action.user
+	case receive:
+		next.Unread++
+	}
+	return &next
+}
+
+type selectProbe struct {
+	id      uuid.UUID
+	renders int
+	value   func() string
+}
+
+func (p *selectProbe) Render() string {
+	p.renders++
+	return `<p id="` + p.id.String() + `">` + p.value() + `</p>`
+}
+func (p *selectProbe) GetID() uuid.UUID         { return p.id }
+func (p *selectProbe) GetChildren() []Component { return nil }
+func (p *selectProbe) InitEventListeners()      {}
+
+func TestStore_RerendersChangedSelections(t *testing.T) {
+	var seen []string
+	record := func(store *Store[session], action any, next func(action any)) {
+		if l, ok := action.(login); ok {
+			next(login{user: "@" + l.user})
+			seen = append(seen, store.Get().User)
+			return
+		}
+		next(action)
+	}
+	store := NewStore(&session{}, reduceSession, LogActions[session](), record)
+
+	header := &selectProbe{id: uuid.New()}
+	user := Select(store, header, func(s *session) string { return s.User })
+	header.value = user.Value
+	badge := &selectProbe{id: uuid.New()}
+	unread := Select(store, badge, func(s *session) int { return s.Unread })
+	badge.value = func() string { return strconv.Itoa(unread.Value()) }
+
+	memory := dom.NewMemory()
+	SetDocument(NewDocumentWithDOM([]Component{header, badge}, memory))
+	defer SetDocument(nil)
+	document.Init()
+
+	store.Dispatch(login{user: "ada"})
+	Flush()
+	store.Dispatch(receive{})
+	store.Dispatch(receive{})
+	Flush()
+
+	if got := memory.GetElementByID(header.id.String()).TextContent(); got != "@ada" {
+		t.Fatalf("user = %q", got)
+	}
+	if got := memory.GetElementByID(badge.id.String()).TextContent(); got != "2" {
+		t.Fatalf("unread = %q", got)
+	}
+	if header.renders != 2 || badge.renders != 2 {
+		t.Fatalf("renders = %d, %d; want 2, 2", header.renders, badge.renders)
+	}
+	if len(seen) != 1 || seen[0] != "@ada" {
+		t.Fatalf("middleware saw %v", seen)
+	}
+
+	killAllStates(header)
+	if len(store.selections) != 1 {
+		t.Fatalf("%d selections after teardown, want 1", len(store.selections))
+	}
+}
@@ -5,10 +5,10 @@ import (
 )
 
 // SwappableComponent manages a dynamic component that can be swapped at runtime
-// It properly cleans up resources (particularly go routines) from the previous component
+// It properly cleans up resources (states and event handlers) from the previous component
 type SwappableComponent struct {
-	id             uuid.UUID
-	current        Component
+	id      uuid.UUID
+	current Component
 }
 
 // NewSwappableComponent creates a new SwappableComponent with an optional initial component
@@ -17,7 +17,7 @@ func NewSwappableComponent(initialComponent Component) *SwappableComponent {
 		id:      uuid.New(),
 		current: initialComponent,
 	}
-	
+
 	return sc
 }
 
@@ -36,21 +36,22 @@ func (sc *SwappableComponent) GetCurrent() Component {
 func (sc *SwappableComponent) Swap(newComponent Component) {
 	// Clean up the old component if it exists
 	if sc.current != nil {
-		// Kill all states associated with the old component and its children
-		// This will stop all go routines associated with the state
+		// Unmount the old component and its children, kill their states so
+		// queued updates to them are dropped, and release their handlers.
+		// The new component is mounted once it has been rendered.
 		killAllStates(sc.current)
-		
+
 		// Log the cleanup
-		logger.Log(DEBUG, "SwappableComponent: Cleaned up previous component: "+sc.current.GetID().String())
+		logger.log("swappable", DEBUG, "Cleaned up previous component", ComponentID(sc.current.GetID()))
 	}
-	
+
 	// Set the new component
 	sc.current = newComponent
-	
+
 	if newComponent != nil {
-		logger.Log(DEBUG, "SwappableComponent: Set new component: "+newComponent.GetID().String())
+		logger.log("swappable", DEBUG, "Set new component", ComponentID(newComponent.GetID()))
 	} else {
-		logger.Log(DEBUG, "SwappableComponent: Set component to nil")
+		logger.log("swappable", DEBUG, "Set component to nil")
 	}
 }
 
@@ -61,26 +62,24 @@ func (sc *SwappableComponent) Render() string {
 	if sc.current == nil {
 		return ""
 	}
-	return sc.current.Render()
+	return renderComponent(sc.current)
 }
 
-// GetChildren delegates to the current component
+// GetChildren returns the current component as the only child, so tree walks
+// (listener setup, teardown, render scheduling) reach it as a component in
+// its own right
 func (sc *SwappableComponent) GetChildren() []Component {
 	if sc.current == nil {
 		return nil
 	}
-	return sc.current.GetChildren()
+	return []Component{sc.current}
 }
 
-// InitEventListeners delegates to the current component
-func (sc *SwappableComponent) InitEventListeners() {
-	if sc.current == nil {
-		return
-	}
-	sc.current.InitEventListeners()
-}
+// InitEventListeners does nothing; the current component initialises its own
+// listeners as a child
+func (sc *SwappableComponent) InitEventListeners() {}
 
 // Cleanup explicitly cleans up resources and removes the current component
 func (sc *SwappableComponent) Cleanup() {
 	sc.Swap(nil) // Swap with nil to clean up the current component
-} 
\ No newline at end of file
+}
@@ -0,0 +1,225 @@
+// Package testing mounts goFE components into an in-memory DOM so they can be
+// driven and inspected from ordinary Go tests:
+//
+//	h := gofetest.Mount(t, counter.NewCounter(&counter.Props{}))
+//	h.Click(raiseButtonID)
+//	if got := h.Query("span").TextContent(); got != "1" {
+//		t.Fatalf("count = %q", got)
+//	}
+//
+// goFE keeps its document in a package variable, so tests using a Harness
+// must not run in parallel with each other.
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+)
+
+// WaitTimeout bounds how long WaitFor keeps flushing before failing the test.
+var WaitTimeout = time.Second
+
+// Harness is a component tree mounted into an in-memory document. Every
+// event helper flushes pending state updates before returning, so the DOM
+// can be asserted on straight away.
+type Harness struct {
+	t        testing.TB
+	memory   *dom.Memory
+	document *goFE.Document
+}
+
+// Mount renders components into the #root element of a fresh in-memory
+// document, which is the current goFE document until the test finishes.
+// The components are then torn down, so their states, Resource loads and
+// window listeners do not leak into later tests.
+func Mount(t testing.TB, components ...goFE.Component) *Harness {
+	t.Helper()
+	return New(t).Mount(components...)
+}
+
+// New creates an in-memory document and makes it the current goFE document
+// until the test finishes, when the components mounted in it are torn down,
+// without mounting anything yet. Use it when
+// components read the document while being constructed, e.g. for the
+// location:
+//
+//	h := gofetest.New(t)
+//	h.DOM().PushState("/about")
+//	h.Mount(router.NewRouter(router.Props{}))
+func New(t testing.TB) *Harness {
+	memory := dom.NewMemory()
+	h := &Harness{
+		t:        t,
+		memory:   memory,
+		document: goFE.NewDocumentWithDOM(nil, memory),
+	}
+	previous := goFE.GetDocument()
+	goFE.SetDocument(h.document)
+	t.Cleanup(func() {
+		goFE.Flush()
+		h.document.Unmount()
+		goFE.SetDocument(previous)
+	})
+	return h
+}
+
+// Mount adds components to the document and renders it.
+func (h *Harness) Mount(components ...goFE.Component) *Harness {
+	for _, component := range components {
+		h.document.Append(component)
+	}
+	h.document.Init()
+	return h
+}
+
+// DOM returns the in-memory document the components are mounted in.
+func (h *Harness) DOM() *dom.Memory {
+	return h.memory
+}
+
+// Document returns the goFE document the components are mounted in.
+func (h *Harness) Document() *goFE.Document {
+	return h.document
+}
+
+// Flush applies pending state updates and renders the affected components.
+func (h *Harness) Flush() {
+	goFE.Flush()
+}
+
+// WaitFor flushes repeatedly until condition holds, for state updated from
+// goroutines started by effects or handlers. The test fails if condition
+// does not hold within WaitTimeout.
+func (h *Harness) WaitFor(condition func() bool) {
+	h.t.Helper()
+	deadline := time.Now().Add(WaitTimeout)
+	for {
+		goFE.Flush()
+		if condition() {
+			return
+		}
+		if time.Now().After(deadline) {
+			h.t.Fatalf("condition not met after %s", WaitTimeout)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// Element returns the element with the given id, failing the test if there
+// is none.
+func (h *Harness) Element(id string) dom.Node {
+	h.t.Helper()
+	element := h.memory.GetElementByID(id)
+	if element == nil {
+		h.t.Fatalf("no element with id %q in:\n%s", id, h.HTML())
+	}
+	return element
+}
+
+// Click clicks the element with the given id. Checkboxes and radio buttons
+// toggle and fire change, and submit buttons submit their form.
+func (h *Harness) Click(id string) dom.Event {
+	h.t.Helper()
+	return h.dispatch(h.Element(id), "click", "")
+}
+
+// Input sets the value of the form control with the given id and fires
+// input and change events on it, as typing and leaving the field would.
+func (h *Harness) Input(id, value string) {
+	h.t.Helper()
+	element := h.Element(id)
+	element.SetValue(value)
+	h.memory.Dispatch(element, "input")
+	h.dispatch(element, "change", "")
+}
+
+// ClickElement clicks element, for elements without an id such as links.
+func (h *Harness) ClickElement(element dom.Node) dom.Event {
+	return h.dispatch(element, "click", "")
+}
+
+// Submit fires submit on the form with the given id.
+func (h *Harness) Submit(id string) dom.Event {
+	h.t.Helper()
+	return h.dispatch(h.Element(id), "submit", "")
+}
+
+// KeyDown fires keydown for key on the element with the given id, with the
+// given modifier keys held.
+func (h *Harness) KeyDown(id, key string, modifiers ...dom.Modifiers) dom.Event {
+	h.t.Helper()
+	return h.dispatch(h.Element(id), "keydown", key, modifiers...)
+}
+
+// Fire fires an arbitrary event on the element with the given id.
+func (h *Harness) Fire(id, event string) dom.Event {
+	h.t.Helper()
+	return h.dispatch(h.Element(id), event, "")
+}
+
+// Back goes back in the session history, firing popstate.
+func (h *Harness) Back() {
+	h.memory.Back()
+	goFE.Flush()
+}
+
+func (h *Harness) dispatch(element dom.Node, event, key string, modifiers ...dom.Modifiers) dom.Event {
+	e := h.memory.DispatchKey(element, event, key, modifiers...)
+	goFE.Flush()
+	return e
+}
+
+// HTML returns the markup inside #root.
+func (h *Harness) HTML() string {
+	return h.memory.GetElementByID("root").InnerHTML()
+}
+
+// Text returns the text content of the element with the given id.
+func (h *Harness) Text(id string) string {
+	h.t.Helper()
+	return h.Element(id).TextContent()
+}
+
+// Query returns the first element inside #root matching selector, or nil.
+// See QueryAll for the supported selectors.
+func (h *Harness) Query(selector string) dom.Node {
+	h.t.Helper()
+	matches := h.QueryAll(selector)
+	if len(matches) == 0 {
+		return nil
+	}
+	return matches[0]
+}
+
+// QueryAll returns the elements inside #root matching selector, in document
+// order. Selectors are simple CSS: tag names, #id, .class, [attr] and
+// [attr=value], combined into compound selectors and separated by spaces
+// for descendants, e.g. "ul.todos li[data-done=true]".
+func (h *Harness) QueryAll(selector string) []dom.Node {
+	h.t.Helper()
+	parsed, err := parseSelector(selector)
+	if err != nil {
+		h.t.Fatalf("invalid selector %q: %v", selector, err)
+	}
+	var matches []dom.Node
+	root := h.memory.GetElementByID("root")
+	walk(root, func(node dom.Node) {
+		if !node.IsSameNode(root) && parsed.matches(node, root) {
+			matches = append(matches, node)
+		}
+	})
+	return matches
+}
+
+func walk(node dom.Node, fn func(dom.Node)) {
+	if node.NodeType() != dom.ElementNode {
+		return
+	}
+	fn(node)
+	for i := 0; i < node.ChildCount(); i++ {
+		walk(node.ChildAt(i), fn)
+	}
+}
@@ -0,0 +1,158 @@
+package testing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cstevenson98/goFE/pkg/goFE"
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+	"github.com/google/uuid"
+)
+
+type todoList struct {
+	id, formID, inputID, clearID uuid.UUID
+
+	items    *goFE.State[[]string]
+	setItems func(*[]string)
+	draft    string
+}
+
+func newTodoList() *todoList {
+	list := &todoList{id: uuid.New(), formID: uuid.New(), inputID: uuid.New(), clearID: uuid.New()}
+	list.items, list.setItems = goFE.NewState[[]string](list, &[]string{})
+	return list
+}
+
+func (l *todoList) Render() string {
+	var sb strings.Builder
+	sb.WriteString(`<div id="` + l.id.String() + `"><form id="` + l.formID.String() + `"><input id="` + l.inputID.String() + `"></form><ul class="todos">`)
+	for _, item := range *l.items.Value {
+		sb.WriteString(`<li data-key="` + item + `">` + item + `</li>`)
+	}
+	sb.WriteString(`</ul><button id="` + l.clearID.String() + `" type="button">Clear</button></div>`)
+	return sb.String()
+}
+func (l *todoList) GetID() uuid.UUID              { return l.id }
+func (l *todoList) GetChildren() []goFE.Component { return nil }
+func (l *todoList) InitEventListeners() {
+	doc := goFE.GetDocument()
+	doc.On(l.inputID, "input", func(event dom.Event) {
+		l.draft = event.Value()
+	})
+	doc.On(l.formID, "submit", func(event dom.Event) {
+		event.PreventDefault()
+		items := append(append([]string{}, *l.items.Value...), l.draft)
+		l.setItems(&items)
+	})
+	doc.On(l.clearID, "click", func(dom.Event) {
+		l.setItems(&[]string{})
+	})
+}
+
+func TestHarness_EventsAndQueries(t *testing.T) {
+	list := newTodoList()
+	h := Mount(t, list)
+
+	h.Input(list.inputID.String(), "milk")
+	if event := h.Submit(list.formID.String()); !event.DefaultPrevented() {
+		t.Fatal("submit handler did not run")
+	}
+	h.Input(list.inputID.String(), "eggs")
+	h.Submit(list.formID.String())
+
+	items := h.QueryAll("ul.todos li")
+	if len(items) != 2 || items[0].TextContent() != "milk" || items[1].TextContent() != "eggs" {
+		t.Fatalf("unexpected items in %s", h.HTML())
+	}
+	if h.Query("li[data-key=eggs]") == nil || h.Query("form li") != nil {
+		t.Fatalf("selector matched the wrong nodes in %s", h.HTML())
+	}
+
+	h.Click(list.clearID.String())
+	if got := h.Text(list.id.String()); got != "Clear" {
+		t.Fatalf("text after clearing = %q", got)
+	}
+}
+
+type pathView struct {
+	id       uuid.UUID
+	linkID   uuid.UUID
+	path     *goFE.State[string]
+	setPath  func(*string)
+	stopPops func()
+}
+
+func newPathView() *pathView {
+	view := &pathView{id: uuid.New(), linkID: uuid.New()}
+	initial := goFE.GetDocument().DOM().Location().Pathname
+	view.path, view.setPath = goFE.NewState[string](view, &initial)
+	return view
+}
+
+func (v *pathView) Render() string {
+	return `<div id="` + v.id.String() + `"><a id="` + v.linkID.String() + `" href="/about">About</a><span>` + *v.path.Value + `</span></div>`
+}
+func (v *pathView) GetID() uuid.UUID              { return v.id }
+func (v *pathView) GetChildren() []goFE.Component { return nil }
+func (v *pathView) InitEventListeners() {
+	doc := goFE.GetDocument()
+	doc.On(v.linkID, "click", func(event dom.Event) {
+		event.PreventDefault()
+		href, _ := event.CurrentTarget().GetAttribute("href")
+		doc.DOM().PushState(href)
+		v.setPath(&href)
+	})
+	if v.stopPops != nil {
+		v.stopPops()
+	}
+	v.stopPops = doc.DOM().AddWindowListener("popstate", func(dom.Event) {
+		path := doc.DOM().Location().Pathname
+		v.setPath(&path)
+	})
+}
+
+func TestHarness_History(t *testing.T) {
+	h := New(t)
+	h.DOM().PushState("/home")
+	view := newPathView()
+	h.Mount(view)
+
+	h.ClickElement(h.Query("a"))
+	if got := h.Query("span").TextContent(); got != "/about" {
+		t.Fatalf("path after navigating = %q", got)
+	}
+	h.Back()
+	if got := h.Query("span").TextContent(); got != "/home" {
+		t.Fatalf("path after going back = %q", got)
+	}
+}
+
+// pending loads a Resource that only finishes when cancelled.
+type pending struct {
+	id        uuid.UUID
+	cancelled chan struct{}
+}
+
+func (p *pending) Render() string                { return `<div id="` + p.id.String() + `"></div>` }
+func (p *pending) GetID() uuid.UUID              { return p.id }
+func (p *pending) GetChildren() []goFE.Component { return nil }
+func (p *pending) InitEventListeners()           {}
+
+func TestHarness_TearsDownOnCleanup(t *testing.T) {
+	p := &pending{id: uuid.New(), cancelled: make(chan struct{})}
+	t.Run("mount", func(t *testing.T) {
+		goFE.NewResource[int](p, func(ctx context.Context) (*int, error) {
+			<-ctx.Done()
+			close(p.cancelled)
+			return nil, ctx.Err()
+		}, nil)
+		Mount(t, p)
+	})
+	select {
+	case <-p.cancelled:
+	case <-time.After(WaitTimeout):
+		t.Fatal("the resource outlived the test it was mounted in")
+	}
+}
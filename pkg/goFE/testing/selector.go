@@ -0,0 +1,126 @@
+package testing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cstevenson98/goFE/pkg/goFE/dom"
+)
+
+// selector is a list of compound selectors, each matching a descendant of
+// an element matched by the one before.
+type selector []compound
+
+type compound struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   []attrMatch
+}
+
+type attrMatch struct {
+	key      string
+	value    string
+	hasValue bool
+}
+
+func parseSelector(source string) (selector, error) {
+	fields := strings.Fields(source)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty selector")
+	}
+	parsed := make(selector, 0, len(fields))
+	for _, field := range fields {
+		c, err := parseCompound(field)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, c)
+	}
+	return parsed, nil
+}
+
+func parseCompound(source string) (compound, error) {
+	var c compound
+	i := 0
+	name := func() string {
+		start := i
+		for i < len(source) && !strings.ContainsRune("#.[", rune(source[i])) {
+			i++
+		}
+		return source[start:i]
+	}
+	c.tag = strings.ToLower(name())
+	for i < len(source) {
+		switch source[i] {
+		case '#':
+			i++
+			c.id = name()
+		case '.':
+			i++
+			c.classes = append(c.classes, name())
+		case '[':
+			end := strings.IndexByte(source[i:], ']')
+			if end < 0 {
+				return c, fmt.Errorf("unterminated attribute selector")
+			}
+			body := source[i+1 : i+end]
+			i += end + 1
+			key, value, hasValue := strings.Cut(body, "=")
+			c.attrs = append(c.attrs, attrMatch{key: key, value: strings.Trim(value, `"'`), hasValue: hasValue})
+		}
+	}
+	return c, nil
+}
+
+// matches reports whether node matches s, looking for ancestors no higher
+// than root.
+func (s selector) matches(node, root dom.Node) bool {
+	if !s[len(s)-1].matches(node) {
+		return false
+	}
+	rest := s[:len(s)-1]
+	for ancestor := node.ParentNode(); len(rest) > 0; ancestor = ancestor.ParentNode() {
+		if ancestor == nil || ancestor.IsSameNode(root) {
+			return false
+		}
+		if rest[len(rest)-1].matches(ancestor) {
+			rest = rest[:len(rest)-1]
+		}
+	}
+	return true
+}
+
+func (c compound) matches(node dom.Node) bool {
+	if c.tag != "" && c.tag != "*" && !strings.EqualFold(node.NodeName(), c.tag) {
+		return false
+	}
+	if c.id != "" && node.ID() != c.id {
+		return false
+	}
+	if len(c.classes) > 0 {
+		class, _ := node.GetAttribute("class")
+		present := strings.Fields(class)
+		for _, want := range c.classes {
+			if !contains(present, want) {
+				return false
+			}
+		}
+	}
+	for _, attr := range c.attrs {
+		value, ok := node.GetAttribute(attr.key)
+		if !ok || (attr.hasValue && value != attr.value) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"syscall/js"
@@ -47,6 +48,11 @@ func (e FetchError) Error() string {
 
 // FetchJSON performs a type-safe JSON fetch request
 func FetchJSON[T any](url string, options *FetchOptions) (*FetchResponse[T], error) {
+	return FetchJSONContext[T](context.Background(), url, options)
+}
+
+// FetchJSONContext is FetchJSON, aborting the request when ctx is done
+func FetchJSONContext[T any](ctx context.Context, url string, options *FetchOptions) (*FetchResponse[T], error) {
 	// Default options
 	if options == nil {
 		options = &FetchOptions{
@@ -96,6 +102,10 @@ func FetchJSON[T any](url string, options *FetchOptions) (*FetchResponse[T], err
 		jsOptions.Set("cache", options.Cache)
 	}
 
+	// Abort the request when ctx is done
+	controller := js.Global().Get("AbortController").New()
+	jsOptions.Set("signal", controller.Get("signal"))
+
 	// Perform the fetch
 	fetchPromise := js.Global().Call("fetch", url, jsOptions)
 
@@ -155,10 +165,16 @@ func FetchJSON[T any](url string, options *FetchOptions) (*FetchResponse[T], err
 
 		case err := <-jsonErrorChan:
 			return nil, err
+		case <-ctx.Done():
+			controller.Call("abort")
+			return nil, ctx.Err()
 		}
 
 	case err := <-errorChan:
 		return nil, err
+	case <-ctx.Done():
+		controller.Call("abort")
+		return nil, ctx.Err()
 	}
 }
 
@@ -431,6 +447,17 @@ func GetJSON[T any](url string) (*FetchResponse[T], error) {
 	return FetchJSON[T](url, &FetchOptions{Method: GET})
 }
 
+// JSONLoader returns a loader for goFE.NewResource that fetches url as JSON
+func JSONLoader[T any](url string, options *FetchOptions) func(ctx context.Context) (*T, error) {
+	return func(ctx context.Context) (*T, error) {
+		response, err := FetchJSONContext[T](ctx, url, options)
+		if err != nil {
+			return nil, err
+		}
+		return &response.Data, nil
+	}
+}
+
 // PostJSON performs a POST request with JSON data
 func PostJSON[T any, U any](url string, data U) (*FetchResponse[T], error) {
 	return FetchJSON[T](url, &FetchOptions{
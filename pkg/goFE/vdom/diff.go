@@ -0,0 +1,201 @@
+package vdom
+
+import "strings"
+
+// PatchOp identifies the DOM operation a Patch performs.
+type PatchOp int
+
+const (
+	// OpSetAttr sets attribute Key to Value on the node at Path.
+	OpSetAttr PatchOp = iota
+	// OpRemoveAttr removes attribute Key from the node at Path.
+	OpRemoveAttr
+	// OpSetText replaces the content of the text or comment node at Path.
+	OpSetText
+	// OpReplace replaces the node at Path with Node.
+	OpReplace
+	// OpInsert inserts Node as child Index of the node at Path.
+	OpInsert
+	// OpRemove removes child Index of the node at Path.
+	OpRemove
+	// OpMove moves child From of the node at Path so it becomes child Index.
+	OpMove
+)
+
+// Patch is a single change needed to turn the old tree into the new one.
+//
+// Path is a list of child indexes from the diffed root. Patches are emitted
+// children-first, so every Path is valid against the tree as it stands when
+// the patch is applied in order: the only structural changes applied before
+// a patch are to lists that its path does not pass through. Indexes for
+// OpInsert, OpRemove and OpMove refer to the child list as already modified
+// by earlier patches on the same parent.
+type Patch struct {
+	Op    PatchOp
+	Path  []int
+	Key   string
+	Value string
+	Index int
+	From  int
+	Node  *Node
+}
+
+// Diff computes the patches needed to turn old into next. Both nodes are
+// expected to be the roots of the same component.
+func Diff(old, next *Node) []Patch {
+	d := differ{}
+	d.diffNode(old, next, nil)
+	return d.patches
+}
+
+// DiffPositional is like Diff but ignores keys and matches children purely
+// by position. It is used when adopting markup rendered elsewhere (e.g. on a
+// server), where the same elements carry different ids.
+func DiffPositional(old, next *Node) []Patch {
+	d := differ{ignoreKeys: true}
+	d.diffNode(old, next, nil)
+	return d.patches
+}
+
+// SameKind reports whether next can be patched in place of old rather than
+// replacing it outright.
+func SameKind(old, next *Node) bool {
+	return sameKind(old, next, false)
+}
+
+func sameKind(old, next *Node, ignoreKeys bool) bool {
+	if old.Type != next.Type {
+		return false
+	}
+	if old.Type != ElementNode {
+		return true
+	}
+	return strings.EqualFold(old.Tag, next.Tag) && (ignoreKeys || old.Key() == next.Key())
+}
+
+type differ struct {
+	ignoreKeys bool
+	patches    []Patch
+}
+
+func (d *differ) key(n *Node) string {
+	if d.ignoreKeys {
+		return ""
+	}
+	return n.Key()
+}
+
+func (d *differ) diffNode(old, next *Node, path []int) {
+	patches := &d.patches
+	if !sameKind(old, next, d.ignoreKeys) {
+		*patches = append(*patches, Patch{Op: OpReplace, Path: path, Node: next})
+		return
+	}
+	if old.Type != ElementNode {
+		if old.Text != next.Text {
+			*patches = append(*patches, Patch{Op: OpSetText, Path: path, Value: next.Text})
+		}
+		return
+	}
+	d.diffChildren(old, next, path)
+	diffAttrs(old, next, path, patches)
+}
+
+func diffAttrs(old, next *Node, path []int, patches *[]Patch) {
+	for _, attr := range next.Attrs {
+		if value, ok := old.Attr(attr.Key); !ok || value != attr.Val {
+			*patches = append(*patches, Patch{Op: OpSetAttr, Path: path, Key: attr.Key, Value: attr.Val})
+		}
+	}
+	for _, attr := range old.Attrs {
+		if _, ok := next.Attr(attr.Key); !ok {
+			*patches = append(*patches, Patch{Op: OpRemoveAttr, Path: path, Key: attr.Key})
+		}
+	}
+}
+
+// diffChildren matches keyed children by key and unkeyed children by order,
+// recurses into matched pairs, then emits the removals, moves and inserts
+// needed to reach the new order.
+func (d *differ) diffChildren(old, next *Node, path []int) {
+	patches := &d.patches
+	oldKeyed := make(map[string]int)
+	var oldUnkeyed []int
+	for i, child := range old.Children {
+		if key := d.key(child); key != "" {
+			oldKeyed[key] = i
+		} else {
+			oldUnkeyed = append(oldUnkeyed, i)
+		}
+	}
+
+	used := make([]bool, len(old.Children))
+	matched := make([]int, len(next.Children))
+	unkeyed := 0
+	for j, child := range next.Children {
+		matched[j] = -1
+		i := -1
+		if key := d.key(child); key != "" {
+			if index, ok := oldKeyed[key]; ok {
+				i = index
+			}
+		} else if unkeyed < len(oldUnkeyed) {
+			i = oldUnkeyed[unkeyed]
+			unkeyed++
+		}
+		if i >= 0 && !used[i] && sameKind(old.Children[i], child, d.ignoreKeys) {
+			matched[j] = i
+			used[i] = true
+		}
+	}
+
+	for j, child := range next.Children {
+		if i := matched[j]; i >= 0 {
+			d.diffNode(old.Children[i], child, childPath(path, i))
+		}
+	}
+
+	// working tracks which old child (or -1 for inserted nodes) sits at
+	// each position as the structural patches are applied.
+	working := make([]int, 0, len(old.Children))
+	for i := range old.Children {
+		working = append(working, i)
+	}
+	for i := len(old.Children) - 1; i >= 0; i-- {
+		if !used[i] {
+			*patches = append(*patches, Patch{Op: OpRemove, Path: path, Index: i})
+			working = append(working[:i], working[i+1:]...)
+		}
+	}
+	for j, child := range next.Children {
+		i := matched[j]
+		if i < 0 {
+			*patches = append(*patches, Patch{Op: OpInsert, Path: path, Index: j, Node: child})
+			working = insertAt(working, j, -1)
+			continue
+		}
+		from := j
+		for from < len(working) && working[from] != i {
+			from++
+		}
+		if from != j {
+			*patches = append(*patches, Patch{Op: OpMove, Path: path, From: from, Index: j})
+			working = append(working[:from], working[from+1:]...)
+			working = insertAt(working, j, i)
+		}
+	}
+}
+
+func childPath(path []int, i int) []int {
+	out := make([]int, len(path)+1)
+	copy(out, path)
+	out[len(path)] = i
+	return out
+}
+
+func insertAt(s []int, index, value int) []int {
+	s = append(s, 0)
+	copy(s[index+1:], s[index:])
+	s[index] = value
+	return s
+}
@@ -0,0 +1,226 @@
+package vdom
+
+import "strings"
+
+// NodeType identifies the kind of a virtual node.
+type NodeType int
+
+const (
+	ElementNode NodeType = iota
+	TextNode
+	CommentNode
+)
+
+// Attribute is a single element attribute. Values are stored unescaped.
+type Attribute struct {
+	Key string
+	Val string
+}
+
+// Node is a virtual DOM node produced by parsing the HTML returned from a
+// component's Render method.
+type Node struct {
+	Type NodeType
+	// Tag is the element name as written in the source, e.g. "div" or "linearGradient".
+	Tag   string
+	Attrs []Attribute
+	// Text holds the unescaped content of text and comment nodes.
+	Text     string
+	Children []*Node
+	Parent   *Node
+}
+
+// Attr returns the value of the named attribute and whether it is present.
+func (n *Node) Attr(key string) (string, bool) {
+	for _, attr := range n.Attrs {
+		if attr.Key == key {
+			return attr.Val, true
+		}
+	}
+	return "", false
+}
+
+// ID returns the id attribute of an element node, or "" if it has none.
+func (n *Node) ID() string {
+	if n.Type != ElementNode {
+		return ""
+	}
+	id, _ := n.Attr("id")
+	return id
+}
+
+// Key returns the identity used to match this node against its previous
+// version when diffing a list of siblings. Elements are keyed by their id
+// attribute, falling back to data-key.
+func (n *Node) Key() string {
+	if n.Type != ElementNode {
+		return ""
+	}
+	if id, ok := n.Attr("id"); ok && id != "" {
+		return id
+	}
+	key, _ := n.Attr("data-key")
+	return key
+}
+
+// AppendChild adds child as the last child of n.
+func (n *Node) AppendChild(child *Node) {
+	child.Parent = n
+	n.Children = append(n.Children, child)
+}
+
+// ReplaceWith swaps n for next in n's parent.
+func (n *Node) ReplaceWith(next *Node) {
+	parent := n.Parent
+	if parent == nil {
+		return
+	}
+	for i, child := range parent.Children {
+		if child == n {
+			parent.Children[i] = next
+			next.Parent = parent
+			n.Parent = nil
+			return
+		}
+	}
+}
+
+// Walk calls fn for n and every descendant in document order.
+func (n *Node) Walk(fn func(*Node)) {
+	fn(n)
+	for _, child := range n.Children {
+		child.Walk(fn)
+	}
+}
+
+// FindByID returns the first node in the subtree rooted at n with the given id.
+func (n *Node) FindByID(id string) *Node {
+	if n.ID() == id {
+		return n
+	}
+	for _, child := range n.Children {
+		if found := child.FindByID(id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Render serialises the node back to HTML.
+func (n *Node) Render() string {
+	var sb strings.Builder
+	n.render(&sb)
+	return sb.String()
+}
+
+// InnerHTML serialises the children of the node.
+func (n *Node) InnerHTML() string {
+	var sb strings.Builder
+	for _, child := range n.Children {
+		child.render(&sb)
+	}
+	return sb.String()
+}
+
+func (n *Node) render(sb *strings.Builder) {
+	switch n.Type {
+	case TextNode:
+		if n.Parent != nil && isRawText(n.Parent.Tag) {
+			sb.WriteString(n.Text)
+		} else {
+			sb.WriteString(EscapeText(n.Text))
+		}
+	case CommentNode:
+		sb.WriteString("<!--")
+		sb.WriteString(n.Text)
+		sb.WriteString("-->")
+	case ElementNode:
+		sb.WriteByte('<')
+		sb.WriteString(n.Tag)
+		for _, attr := range n.Attrs {
+			sb.WriteByte(' ')
+			sb.WriteString(attr.Key)
+			sb.WriteString(`="`)
+			sb.WriteString(EscapeAttr(attr.Val))
+			sb.WriteByte('"')
+		}
+		if isVoid(n.Tag) {
+			sb.WriteByte('>')
+			return
+		}
+		if len(n.Children) == 0 && n.IsForeign() {
+			sb.WriteString("/>")
+			return
+		}
+		sb.WriteByte('>')
+		for _, child := range n.Children {
+			child.render(sb)
+		}
+		sb.WriteString("</")
+		sb.WriteString(n.Tag)
+		sb.WriteByte('>')
+	}
+}
+
+// IsForeign reports whether the node lives in SVG or MathML content, where
+// tag and attribute names are case-sensitive and self-closing tags are honoured.
+func (n *Node) IsForeign() bool {
+	for node := n; node != nil; node = node.Parent {
+		if node.Type == ElementNode && (strings.EqualFold(node.Tag, "svg") || strings.EqualFold(node.Tag, "math")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Namespace returns the XML namespace an element belongs to, or "" for HTML.
+func (n *Node) Namespace() string {
+	for node := n; node != nil; node = node.Parent {
+		if node.Type != ElementNode {
+			continue
+		}
+		if strings.EqualFold(node.Tag, "svg") {
+			return "http://www.w3.org/2000/svg"
+		}
+		if strings.EqualFold(node.Tag, "math") {
+			return "http://www.w3.org/1998/Math/MathML"
+		}
+	}
+	return ""
+}
+
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"source": true, "track": true, "wbr": true,
+}
+
+// textKind is how the parser reads the content of an element whose content
+// is text, not markup.
+type textKind int
+
+const (
+	// rawText content is kept as is, character references included, and
+	// written back unescaped.
+	rawText textKind = iota + 1
+	// escapableRawText content has its character references decoded and is
+	// escaped again when written.
+	escapableRawText
+)
+
+// rawTextElements holds the elements whose content is text, and of which
+// kind.
+var rawTextElements = map[string]textKind{
+	"script": rawText, "style": rawText,
+	"textarea": escapableRawText, "title": escapableRawText,
+}
+
+func isVoid(tag string) bool {
+	return voidElements[strings.ToLower(tag)]
+}
+
+// isRawText reports whether the content of tag is kept and written
+// unescaped.
+func isRawText(tag string) bool {
+	return rawTextElements[strings.ToLower(tag)] == rawText
+}
@@ -0,0 +1,379 @@
+package vdom
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Parse turns an HTML fragment into a list of top-level virtual nodes.
+//
+// The parser is deliberately small: it understands elements, attributes,
+// void and raw-text elements, comments and the handful of implicit end tags
+// templates commonly rely on. Anything the browser would reshape further
+// (e.g. implied <tbody>) is caught when patching and falls back to a full swap.
+func Parse(src string) []*Node {
+	root := &Node{Type: ElementNode}
+	p := &parser{src: src, stack: []*Node{root}}
+	p.parse()
+	children := root.Children
+	for _, child := range children {
+		child.Parent = nil
+	}
+	return children
+}
+
+// ParseInto parses src and appends the resulting nodes as children of parent.
+func ParseInto(parent *Node, src string) {
+	for _, child := range Parse(src) {
+		parent.AppendChild(child)
+	}
+}
+
+type parser struct {
+	src   string
+	pos   int
+	stack []*Node
+}
+
+func (p *parser) current() *Node {
+	return p.stack[len(p.stack)-1]
+}
+
+func (p *parser) parse() {
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			p.parseComment()
+		case strings.HasPrefix(rest, "</"):
+			p.parseEndTag()
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			// Doctype and processing instructions have no place in a fragment.
+			p.skipPast(">")
+		case len(rest) > 1 && rest[0] == '<' && isLetter(rest[1]):
+			p.parseStartTag()
+		default:
+			p.parseText()
+		}
+	}
+}
+
+func (p *parser) skipPast(marker string) {
+	end := strings.Index(p.src[p.pos:], marker)
+	if end < 0 {
+		p.pos = len(p.src)
+		return
+	}
+	p.pos += end + len(marker)
+}
+
+func (p *parser) parseComment() {
+	start := p.pos + len("<!--")
+	end := strings.Index(p.src[start:], "-->")
+	if end < 0 {
+		p.current().AppendChild(&Node{Type: CommentNode, Text: p.src[start:]})
+		p.pos = len(p.src)
+		return
+	}
+	p.current().AppendChild(&Node{Type: CommentNode, Text: p.src[start : start+end]})
+	p.pos = start + end + len("-->")
+}
+
+func (p *parser) parseText() {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		if p.src[p.pos] == '<' && p.pos+1 < len(p.src) {
+			next := p.src[p.pos+1]
+			if isLetter(next) || next == '/' || next == '!' || next == '?' {
+				break
+			}
+		}
+		p.pos++
+	}
+	p.appendText(Unescape(p.src[start:p.pos]))
+}
+
+func (p *parser) appendText(text string) {
+	if text == "" {
+		return
+	}
+	parent := p.current()
+	if n := len(parent.Children); n > 0 && parent.Children[n-1].Type == TextNode {
+		parent.Children[n-1].Text += text
+		return
+	}
+	parent.AppendChild(&Node{Type: TextNode, Text: text})
+}
+
+func (p *parser) parseEndTag() {
+	p.pos += len("</")
+	name := p.readName()
+	p.skipPast(">")
+	if name == "" {
+		return
+	}
+	for i := len(p.stack) - 1; i > 0; i-- {
+		if strings.EqualFold(p.stack[i].Tag, name) {
+			p.stack = p.stack[:i]
+			return
+		}
+	}
+}
+
+func (p *parser) parseStartTag() {
+	p.pos++
+	name := p.readName()
+	foreign := p.current().IsForeign() || strings.EqualFold(name, "svg") || strings.EqualFold(name, "math")
+	if !foreign {
+		name = strings.ToLower(name)
+		p.closeImplied(name)
+	}
+	element := &Node{Type: ElementNode, Tag: name}
+	selfClosing := p.parseAttributes(element, foreign)
+	p.current().AppendChild(element)
+
+	if isVoid(name) || (selfClosing && foreign) {
+		return
+	}
+	if _, ok := rawTextElements[name]; ok && !foreign {
+		p.parseRawText(element)
+		return
+	}
+	p.stack = append(p.stack, element)
+}
+
+// parseAttributes reads attributes up to the end of the start tag and reports
+// whether the tag was written self-closing.
+func (p *parser) parseAttributes(element *Node, foreign bool) bool {
+	for p.pos < len(p.src) {
+		p.skipSpace()
+		if p.pos >= len(p.src) {
+			return false
+		}
+		switch p.src[p.pos] {
+		case '>':
+			p.pos++
+			return false
+		case '/':
+			p.pos++
+			if p.pos < len(p.src) && p.src[p.pos] == '>' {
+				p.pos++
+				return true
+			}
+			continue
+		}
+		start := p.pos
+		for p.pos < len(p.src) && !isSpace(p.src[p.pos]) && !strings.ContainsRune("=>/", rune(p.src[p.pos])) {
+			p.pos++
+		}
+		key := p.src[start:p.pos]
+		if key == "" {
+			// Stray '=' or similar; skip it so we always make progress.
+			p.pos++
+			continue
+		}
+		if !foreign {
+			key = strings.ToLower(key)
+		}
+		value := ""
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == '=' {
+			p.pos++
+			p.skipSpace()
+			value = Unescape(p.readAttrValue())
+		}
+		if _, exists := element.Attr(key); !exists {
+			element.Attrs = append(element.Attrs, Attribute{Key: key, Val: value})
+		}
+	}
+	return false
+}
+
+func (p *parser) readAttrValue() string {
+	if p.pos >= len(p.src) {
+		return ""
+	}
+	quote := p.src[p.pos]
+	if quote == '"' || quote == '\'' {
+		p.pos++
+		end := strings.IndexByte(p.src[p.pos:], quote)
+		if end < 0 {
+			value := p.src[p.pos:]
+			p.pos = len(p.src)
+			return value
+		}
+		value := p.src[p.pos : p.pos+end]
+		p.pos += end + 1
+		return value
+	}
+	start := p.pos
+	for p.pos < len(p.src) && !isSpace(p.src[p.pos]) && p.src[p.pos] != '>' {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) parseRawText(element *Node) {
+	closing := "</" + element.Tag
+	end := indexFold(p.src[p.pos:], closing)
+	var text string
+	if end < 0 {
+		text = p.src[p.pos:]
+		p.pos = len(p.src)
+	} else {
+		text = p.src[p.pos : p.pos+end]
+		p.pos += end
+		p.skipPast(">")
+	}
+	if !isRawText(element.Tag) {
+		text = Unescape(text)
+	}
+	if element.Tag == "textarea" {
+		text = strings.TrimPrefix(text, "\n")
+	}
+	if text != "" {
+		element.AppendChild(&Node{Type: TextNode, Text: text})
+	}
+}
+
+// closeImplied pops elements whose end tag is implied by the start of tag,
+// mirroring the most common HTML auto-closing rules.
+func (p *parser) closeImplied(tag string) {
+	for len(p.stack) > 1 {
+		open := p.current().Tag
+		if !impliedEnd(open, tag) {
+			return
+		}
+		p.stack = p.stack[:len(p.stack)-1]
+	}
+}
+
+var closesParagraph = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "div": true,
+	"dl": true, "fieldset": true, "footer": true, "form": true, "h1": true, "h2": true,
+	"h3": true, "h4": true, "h5": true, "h6": true, "header": true, "hr": true,
+	"main": true, "nav": true, "ol": true, "p": true, "pre": true, "section": true,
+	"table": true, "ul": true,
+}
+
+func impliedEnd(open, next string) bool {
+	switch open {
+	case "p":
+		return closesParagraph[next]
+	case "li":
+		return next == "li"
+	case "option":
+		return next == "option" || next == "optgroup"
+	case "dt", "dd":
+		return next == "dt" || next == "dd"
+	case "td", "th":
+		return next == "td" || next == "th" || next == "tr"
+	case "tr":
+		return next == "tr"
+	}
+	return false
+}
+
+func (p *parser) readName() string {
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if isSpace(c) || c == '>' || c == '/' {
+			break
+		}
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && isSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func indexFold(s, substr string) int {
+	n := len(substr)
+	for i := 0; i+n <= len(s); i++ {
+		if strings.EqualFold(s[i:i+n], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// namedEntities covers the entities quicktemplate emits plus a few common
+// hand-written ones. Unknown entities are left as written.
+var namedEntities = map[string]string{
+	"amp": "&", "lt": "<", "gt": ">", "quot": `"`, "apos": "'",
+	"nbsp": " ", "copy": "©", "reg": "®", "trade": "™",
+	"hellip": "…", "mdash": "—", "ndash": "–", "middot": "·",
+	"laquo": "«", "raquo": "»", "times": "×", "eacute": "é",
+}
+
+// Unescape decodes HTML character references in s.
+func Unescape(s string) string {
+	if !strings.Contains(s, "&") {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '&' {
+			sb.WriteByte(s[i])
+			continue
+		}
+		end := strings.IndexByte(s[i:], ';')
+		if end < 2 || end > 10 {
+			sb.WriteByte('&')
+			continue
+		}
+		ref := s[i+1 : i+end]
+		if decoded, ok := decodeEntity(ref); ok {
+			sb.WriteString(decoded)
+			i += end
+			continue
+		}
+		sb.WriteByte('&')
+	}
+	return sb.String()
+}
+
+func decodeEntity(ref string) (string, bool) {
+	if ref[0] != '#' {
+		decoded, ok := namedEntities[ref]
+		return decoded, ok
+	}
+	var code uint64
+	var err error
+	if len(ref) > 1 && (ref[1] == 'x' || ref[1] == 'X') {
+		code, err = strconv.ParseUint(ref[2:], 16, 32)
+	} else {
+		code, err = strconv.ParseUint(ref[1:], 10, 32)
+	}
+	if err != nil {
+		return "", false
+	}
+	return string(rune(code)), true
+}
+
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+var attrEscaper = strings.NewReplacer("&", "&amp;", `"`, "&quot;", "<", "&lt;", ">", "&gt;")
+
+// EscapeText escapes s for use as HTML text content.
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// EscapeAttr escapes s for use inside a double-quoted attribute value.
+func EscapeAttr(s string) string {
+	return attrEscaper.Replace(s)
+}
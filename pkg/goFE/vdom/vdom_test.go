@@ -0,0 +1,202 @@
+package vdom
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Nested elements",
+			input:    `<div id="a" class="x"><span>hi</span></div>`,
+			expected: `<div id="a" class="x"><span>hi</span></div>`,
+		},
+		{
+			name:     "Void and boolean attributes",
+			input:    `<input id=name type=text required><br/>`,
+			expected: `<input id="name" type="text" required=""><br>`,
+		},
+		{
+			name:     "Entities are decoded and re-escaped",
+			input:    `<p title="a &quot;b&quot;">&lt;3 &amp; &copy;</p>`,
+			expected: `<p title="a &quot;b&quot;">&lt;3 &amp; ©</p>`,
+		},
+		{
+			name:     "SVG keeps case and self-closing tags",
+			input:    `<svg viewBox="0 0 1 1"><use href="x" /><linearGradient/></svg>`,
+			expected: `<svg viewBox="0 0 1 1"><use href="x"/><linearGradient/></svg>`,
+		},
+		{
+			name:     "Implied end tags",
+			input:    `<ul><li>a<li>b</ul><p>one<p>two`,
+			expected: `<ul><li>a</li><li>b</li></ul><p>one</p><p>two</p>`,
+		},
+		{
+			name:     "Textarea content is text",
+			input:    "<textarea>\n<b>not bold</b></textarea>",
+			expected: `<textarea>&lt;b&gt;not bold&lt;/b&gt;</textarea>`,
+		},
+		{
+			name:     "Script content is kept unescaped, title content escaped",
+			input:    `<script>if (a < b && c) {}</script><title>a &amp; <b></title>`,
+			expected: `<script>if (a < b && c) {}</script><title>a &amp; &lt;b&gt;</title>`,
+		},
+		{
+			name:     "Comments and surrounding whitespace",
+			input:    "\n  <div><!-- note --></div>\n",
+			expected: "\n  <div><!-- note --></div>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out string
+			for _, node := range Parse(tt.input) {
+				out += node.Render()
+			}
+			if out != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestDiff_AppliesCleanly(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		next    string
+		maxOps  int
+		allowed []PatchOp
+	}{
+		{
+			name:    "Text change only",
+			old:     `<div id="c"><span>1</span></div>`,
+			next:    `<div id="c"><span>2</span></div>`,
+			maxOps:  1,
+			allowed: []PatchOp{OpSetText},
+		},
+		{
+			name:    "Attribute change keeps input",
+			old:     `<div id="c"><input id="i" value="a"><p class="x">t</p></div>`,
+			next:    `<div id="c"><input id="i" value="a"><p class="y">t</p></div>`,
+			maxOps:  1,
+			allowed: []PatchOp{OpSetAttr},
+		},
+		{
+			name:    "Keyed children reorder",
+			old:     `<ul id="c"><li id="a">a</li><li id="b">b</li><li id="d">d</li></ul>`,
+			next:    `<ul id="c"><li id="d">d</li><li id="a">a</li><li id="b">b</li></ul>`,
+			maxOps:  2,
+			allowed: []PatchOp{OpMove},
+		},
+		{
+			name:   "Keyed insert in the middle",
+			old:    `<ul id="c"><li id="a">a</li><li id="b">b</li></ul>`,
+			next:   `<ul id="c"><li id="a">a</li><li id="n">n</li><li id="b">b</li></ul>`,
+			maxOps: 1,
+		},
+		{
+			name: "Mixed removal, insertion and nested changes",
+			old:  `<div id="c"><h1>T</h1><div id="x"><b>1</b></div><div id="y">y</div><p>p</p></div>`,
+			next: `<div id="c"><div id="y">y!</div><h1>T2</h1><div id="z">z</div><div id="x"><i>1</i></div></div>`,
+		},
+		{
+			name:   "Root tag change",
+			old:    `<div id="c">a</div>`,
+			next:   `<section id="c">a</section>`,
+			maxOps: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			holder := &Node{Type: ElementNode, Tag: "div"}
+			ParseInto(holder, tt.old)
+			next := Parse(tt.next)[0]
+
+			patches := Diff(holder.Children[0], next)
+			if tt.maxOps > 0 && len(patches) > tt.maxOps {
+				t.Errorf("Expected at most %d patches, got %d: %+v", tt.maxOps, len(patches), patches)
+			}
+			for _, patch := range patches {
+				if tt.allowed != nil && !containsOp(tt.allowed, patch.Op) {
+					t.Errorf("Unexpected patch op %d", patch.Op)
+				}
+			}
+			if err := applyTo(holder, patches); err != nil {
+				t.Fatalf("Expected patches to apply, got: %v", err)
+			}
+			if got := holder.InnerHTML(); got != tt.next {
+				t.Errorf("Expected %q after patching, got %q", tt.next, got)
+			}
+		})
+	}
+}
+
+func containsOp(ops []PatchOp, op PatchOp) bool {
+	for _, candidate := range ops {
+		if candidate == op {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTo applies patches to the single child of holder, standing in for a
+// live DOM. Inserted nodes are rendered and reparsed so the tree under test
+// never shares nodes with the patch source.
+func applyTo(holder *Node, patches []Patch) error {
+	for _, patch := range patches {
+		target := holder.Children[0]
+		for _, index := range patch.Path {
+			if index >= len(target.Children) {
+				return fmt.Errorf("bad path %v", patch.Path)
+			}
+			target = target.Children[index]
+		}
+		switch patch.Op {
+		case OpSetAttr:
+			set := false
+			for i := range target.Attrs {
+				if target.Attrs[i].Key == patch.Key {
+					target.Attrs[i].Val, set = patch.Value, true
+				}
+			}
+			if !set {
+				target.Attrs = append(target.Attrs, Attribute{Key: patch.Key, Val: patch.Value})
+			}
+		case OpRemoveAttr:
+			for i := range target.Attrs {
+				if target.Attrs[i].Key == patch.Key {
+					target.Attrs = append(target.Attrs[:i], target.Attrs[i+1:]...)
+					break
+				}
+			}
+		case OpSetText:
+			target.Text = patch.Value
+		case OpReplace:
+			target.ReplaceWith(Parse(patch.Node.Render())[0])
+		case OpInsert:
+			node := Parse(patch.Node.Render())[0]
+			node.Parent = target
+			target.Children = append(target.Children, nil)
+			copy(target.Children[patch.Index+1:], target.Children[patch.Index:])
+			target.Children[patch.Index] = node
+		case OpRemove:
+			target.Children = append(target.Children[:patch.Index], target.Children[patch.Index+1:]...)
+		case OpMove:
+			node := target.Children[patch.From]
+			target.Children = append(target.Children[:patch.From], target.Children[patch.From+1:]...)
+			target.Children = append(target.Children, nil)
+			copy(target.Children[patch.Index+1:], target.Children[patch.Index:])
+			target.Children[patch.Index] = node
+		}
+	}
+	return nil
+}